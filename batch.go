@@ -0,0 +1,160 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// batchEntry is the manifest line of one program run by hw7 run.
+type batchEntry struct {
+	Program  string
+	Result   string `json:",omitempty"`
+	Status   string
+	Duration float64 // seconds
+	Error    string  `json:",omitempty"`
+}
+
+// runBatch evaluates every .json program below a directory in parallel. It
+// writes each result to the same relative path below -out and a
+// manifest.json describing all runs.
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	out := fs.String("out", "results", "directory for results and manifest.json")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "programs evaluated at the same time")
+	// flags may come before or after the directory
+	var dirs []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		dirs = append(dirs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(dirs) != 1 {
+		return fmt.Errorf("usage: hw7 run dir/ [--out results/] [--jobs n]")
+	}
+	if *jobs < 1 {
+		return fmt.Errorf("usage: hw7 run dir/ [--out results/] [--jobs n], n must be at least 1")
+	}
+	dir := dirs[0]
+	var programs []string
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && filepath.Clean(file) == filepath.Clean(*out) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && strings.HasSuffix(file, ".json") {
+			programs = append(programs, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(programs)
+	manifest := make([]batchEntry, len(programs))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				manifest[i] = runBatchProgram(dir, programs[i], *out)
+			}
+		}()
+	}
+	for i := range programs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(*out, "manifest.json"), append(raw, '\n'), 0644); err != nil {
+		return err
+	}
+	failed := 0
+	for _, e := range manifest {
+		if e.Status != "ok" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d programs failed, see %s", failed, len(manifest), filepath.Join(*out, "manifest.json"))
+	}
+	return nil
+}
+
+// runBatchProgram evaluates one program file and writes its result.
+func runBatchProgram(dir string, file string, out string) batchEntry {
+	rel, _ := filepath.Rel(dir, file)
+	entry := batchEntry{Program: rel, Status: "error"}
+	start := time.Now()
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	result, err := evalProgram(raw)
+	entry.Duration = time.Since(start).Seconds()
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	target := filepath.Join(out, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	if err := ioutil.WriteFile(target, []byte(result+"\n"), 0644); err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Result = rel
+	entry.Status = "ok"
+	return entry
+}
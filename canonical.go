@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+var outputFormat = flag.String("format", "json", "result format: json or json-canonical (sorted keys, fixed number formatting, no whitespace)")
+
+// checkFormat rejects unknown values of -format.
+func checkFormat() error {
+	switch *outputFormat {
+	case "json", "json-canonical":
+		return nil
+	}
+	return fmt.Errorf("unknown format %s", *outputFormat)
+}
+
+// canonicalJSON rewrites a printed result so equal results give equal
+// bytes: object keys are sorted, numbers use the shortest form that reads
+// back to the same float64, without exponent between 1e-6 and 1e21, and
+// negative zero prints as 0.
+func canonicalJSON(s string) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(positiveZero(data)); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSuffix(b.Bytes(), []byte("\n"))), nil
+}
+
+// positiveZero replaces -0 in data by 0.
+func positiveZero(data interface{}) interface{} {
+	switch dt := data.(type) {
+	case float64:
+		if dt == 0 {
+			return 0.0
+		}
+	case []interface{}:
+		for i := range dt {
+			dt[i] = positiveZero(dt[i])
+		}
+	case map[string]interface{}:
+		for k := range dt {
+			dt[k] = positiveZero(dt[k])
+		}
+	}
+	return data
+}
@@ -0,0 +1,155 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+var maxNodes = flag.Int64("max-nodes", 1000000, "node limit checked by hw7 cost")
+var maxWidth = flag.Int64("max-width", 10000, "limit on the arguments evaluated in parallel by one node, checked by hw7 cost")
+var maxEnvCopy = flag.Int64("max-env-copy", 10000000, "limit on the environment entries copied by Let, checked by hw7 cost")
+
+// costEstimate is the static estimate printed by hw7 cost.
+type costEstimate struct {
+	Nodes         int64 // nodes evaluated
+	Goroutines    int64 // goroutines started, one per node not evaluated sequentially
+	MaxWidth      int64 // most sibling nodes evaluated in parallel
+	EnvCopyVolume int64 // environment entries copied by all Lets
+	Warnings      []string
+}
+
+// runCost prints the cost estimate of a program file and fails if a limit
+// would be exceeded.
+func runCost(file string) error {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	envSize := int64(2) // Nowhere and Everywhere
+	if *useStdlib {
+		envSize += int64(len(stdlib()))
+	}
+	envSize += int64(len(defines))
+	est := &costEstimate{}
+	seq := *evalStrategy == "sequential"
+	est.add(data, envSize, seq)
+	if seq {
+		est.Goroutines++ // the root always runs in its own
+	}
+	for _, limit := range []struct {
+		name  string
+		value int64
+		max   int64
+	}{
+		{"nodes", est.Nodes, *maxNodes},
+		{"parallel width", est.MaxWidth, *maxWidth},
+		{"environment copy volume", est.EnvCopyVolume, *maxEnvCopy},
+	} {
+		if limit.value > limit.max {
+			est.Warnings = append(est.Warnings, fmt.Sprintf("%s %d exceeds limit %d", limit.name, limit.value, limit.max))
+		}
+	}
+	out, err := json.Marshal(est)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	if len(est.Warnings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// add accounts for the node data evaluated in an environment of envSize
+// entries, run in its own goroutine unless its strategy is sequential.
+func (est *costEstimate) add(data interface{}, envSize int64, seq bool) {
+	est.Nodes++
+	if !seq {
+		est.Goroutines++
+	}
+	dt, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+	childSeq := seq
+	if strategy, ok := dt["strategy"]; ok {
+		est.EnvCopyVolume += envSize
+		childSeq = strategy == "sequential"
+	}
+	if vars, ok := dt["Let"].(map[string]interface{}); ok {
+		if !childSeq {
+			est.width(int64(len(vars)))
+		}
+		est.EnvCopyVolume += envSize + int64(len(vars))
+		for _, exp := range vars {
+			est.add(exp, envSize, childSeq)
+		}
+		if in, ok := dt["in"]; ok {
+			est.add(in, envSize+int64(len(vars)), childSeq)
+		}
+		return
+	}
+	for key, value := range dt {
+		if key == "style" || key == "uncertain" || key == "strategy" {
+			continue
+		}
+		args, ok := value.([]interface{})
+		if !ok {
+			est.add(value, envSize, childSeq)
+			continue
+		}
+		var width int64
+		for _, arg := range args {
+			// coordinate pairs, e.g. in Path, are evaluated as one list
+			if pair, ok := arg.([]interface{}); ok {
+				for _, coord := range pair {
+					est.add(coord, envSize, childSeq)
+					width++
+				}
+			} else {
+				est.add(arg, envSize, childSeq)
+				width++
+			}
+		}
+		if !childSeq {
+			est.width(width)
+		}
+	}
+}
+
+func (est *costEstimate) width(w int64) {
+	if w > est.MaxWidth {
+		est.MaxWidth = w
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// definition is a binding given with -define.
+type definition struct {
+	name string
+	data interface{}
+}
+
+// definitions collects the -define flags in order.
+type definitions []definition
+
+func (ds *definitions) String() string {
+	s := make([]string, len(*ds))
+	for i, d := range *ds {
+		s[i] = d.name
+	}
+	return strings.Join(s, ",")
+}
+func (ds *definitions) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected name=expr, got %q", value)
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(value[i+1:]), &data); err != nil {
+		return fmt.Errorf("%s: %v", value[:i], err)
+	}
+	*ds = append(*ds, definition{value[:i], data})
+	return nil
+}
+
+var defines definitions
+
+func init() {
+	flag.Var(&defines, "define", "bind a name to a DSL expression before evaluating, given as `name=expr`; repeatable, later definitions may use earlier ones")
+}
+
+// define evaluates the -define bindings into env, one after the other.
+func define(env map[string]interface{}) {
+	for _, d := range defines {
+		c := make(chan interface{}, 1)
+		go getValue(d.data, env, c, "$define."+d.name)
+		env[d.name] = receive(c)
+	}
+}
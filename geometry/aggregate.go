@@ -0,0 +1,101 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Kind is the name a value is printed under, e.g. "Point".
+type Kind string
+
+// KindOf returns the Kind of v from its type.
+func KindOf(v Value) Kind {
+	return Kind(kind(v))
+}
+
+// CombinedBounds returns the bounding box of the bounded values in vs and
+// whether there are any. The box may be degenerate.
+func CombinedBounds(vs []Value) (Rect, bool) {
+	box := Rect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	bounded := false
+	for _, v := range vs {
+		if minX, minY, maxX, maxY, ok := valueBounds(v); ok {
+			box.minX, box.minY = math.Min(box.minX, minX), math.Min(box.minY, minY)
+			box.maxX, box.maxY = math.Max(box.maxX, maxX), math.Max(box.maxY, maxY)
+			bounded = true
+		}
+	}
+	if !bounded {
+		return Rect{}, false
+	}
+	return box, true
+}
+
+// TotalLength is the length of all segments, curves and outlines in vs.
+func TotalLength(vs []Value) float64 {
+	return Summarize(vs).Length
+}
+func CountByKind(vs []Value) map[Kind]int {
+	counts := map[Kind]int{}
+	for _, v := range vs {
+		counts[KindOf(v)]++
+	}
+	return counts
+}
+
+// Aggregate holds the bounds, total length and counts of a list of values.
+// It prints as a JSON object, with null bounds if no value is bounded.
+type Aggregate struct {
+	Bounds  Rect
+	Bounded bool
+	Length  float64
+	Counts  map[Kind]int
+}
+
+/* Aggregate */
+func NewAggregate(vs []Value) Aggregate {
+	bounds, bounded := CombinedBounds(vs)
+	return Aggregate{bounds, bounded, TotalLength(vs), CountByKind(vs)}
+}
+func (a Aggregate) GoString() string {
+	bounds := "null"
+	if a.Bounded {
+		bounds = fmt.Sprintf("[%v,%v,%v,%v]", a.Bounds.minX, a.Bounds.minY, a.Bounds.maxX, a.Bounds.maxY)
+	}
+	kinds := make([]string, 0, len(a.Counts))
+	for k := range a.Counts {
+		kinds = append(kinds, string(k))
+	}
+	sort.Strings(kinds)
+	counts := make([]string, len(kinds))
+	for i, k := range kinds {
+		counts[i] = fmt.Sprintf("%q:%d", k, a.Counts[Kind(k)])
+	}
+	return fmt.Sprintf("{\"Bounds\":%s,\"Length\":%v,\"Counts\":{%s}}", bounds, a.Length, strings.Join(counts, ","))
+}
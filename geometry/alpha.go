@@ -0,0 +1,60 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// AlphaShape outlines points by the Delaunay triangles whose circumradius
+// is at most alpha. Large alpha approaches the convex hull, small alpha
+// follows the points closely. Separate clusters come back together in a
+// Collection, and an outline with holes as polygons without holes covering
+// it, as from Difference. Nowhere is left if no triangle is small enough.
+func AlphaShape(points []Point, alpha float64) Value {
+	pts := distinctPoints(points)
+	var kept []triangleIndex
+	for _, t := range delaunay(pts) {
+		if _, r := circumcircle(pts[t[0]], pts[t[1]], pts[t[2]]); r <= alpha {
+			kept = append(kept, t)
+		}
+	}
+	// edges used by exactly one kept triangle form the outline
+	count := map[[2]int]int{}
+	for _, t := range kept {
+		for k := 0; k < 3; k++ {
+			count[[2]int{t[k], t[(k+1)%3]}]++
+		}
+	}
+	var edges []piece
+	for e := range count {
+		if count[[2]int{e[1], e[0]}] == 0 {
+			edges = append(edges, piece{from: pts[e[0]], to: pts[e[1]]})
+		}
+	}
+	var parts []Value
+	for _, pw := range groupHoles(linkRings(edges)) {
+		for _, pg := range withoutHoles(pw) {
+			parts = append(parts, pg)
+		}
+	}
+	return newCollection(parts)
+}
@@ -0,0 +1,208 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+/* arc: counterclockwise from angle start over sweep along a circle */
+type Arc struct {
+	x     float64
+	y     float64
+	r     float64
+	start float64
+	sweep float64
+}
+
+// NewArc returns the arc of the circle around (x, y) with radius r running
+// counterclockwise from angle start to angle end. Angles a full turn apart
+// give the whole circle.
+func NewArc(x float64, y float64, r float64, start float64, end float64) Value {
+	if r < 0 {
+		panic("An Arc needs a non-negative radius")
+	}
+	if realClose(r, 0) {
+		return Point{x, y}
+	}
+	sweep := normalizeAngle(end - start)
+	if realClose(sweep*r, 0) || realClose(sweep*r, 2*math.Pi*r) {
+		if realClose(start, end) {
+			return Point{x + r*math.Cos(start), y + r*math.Sin(start)}
+		}
+		return Circle{x, y, r}
+	}
+	return Arc{x, y, r, normalizeAngle(start), sweep}
+}
+func (a Arc) shift(dx float64, dy float64) Value {
+	return Arc{a.x + dx, a.y + dy, a.r, a.start, a.sweep}
+}
+func (a Arc) mirror(fx float64, fy float64) Value {
+	s := a.At(0)
+	if fx*fy < 0 {
+		// a mirror image runs clockwise, so it starts at the old end
+		s = a.At(1)
+	}
+	start := math.Atan2(fy*(s.y-a.y), fx*(s.x-a.x))
+	return Arc{fx * a.x, fy * a.y, a.r, normalizeAngle(start), a.sweep}
+}
+func (a Arc) scale(sx float64, sy float64) Value {
+	if !realClose(math.Abs(sx), math.Abs(sy)) {
+		// an elliptic arc, which Beziers can follow
+		return a.toBeziers().scale(sx, sy)
+	}
+	s := a.At(0)
+	if sx*sy < 0 {
+		s = a.At(1)
+	}
+	start := math.Atan2(sy*(s.y-a.y), sx*(s.x-a.x))
+	return Arc{sx * a.x, sy * a.y, math.Abs(sx) * a.r, normalizeAngle(start), a.sweep}
+}
+func (a Arc) rotate(theta float64) Value {
+	c := Point{a.x, a.y}.rotate(theta).(Point)
+	return Arc{c.x, c.y, a.r, normalizeAngle(a.start + theta), a.sweep}
+}
+func (a Arc) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return a
+	case Arc:
+		if realClose(a.x, ot.x) && realClose(a.y, ot.y) && realClose(a.r, ot.r) {
+			return a.overlap(ot)
+		}
+		return ot.clip(a.clip(Circle{a.x, a.y, a.r}.intersect(Circle{ot.x, ot.y, ot.r})))
+	case Circle:
+		if realClose(a.x, ot.x) && realClose(a.y, ot.y) && realClose(a.r, ot.r) {
+			return a
+		}
+		return a.clip(Circle{a.x, a.y, a.r}.intersect(ot))
+	case Point, Line, LineSegment, Bezier, Ray:
+		return a.clip(Circle{a.x, a.y, a.r}.intersect(ot))
+	case pointSet, compoundCurve, Path, Polygon, Rect, Triangle, collection:
+		return ot.intersect(a)
+	}
+	panic("Should never been reached")
+}
+func (a Arc) GoString() string {
+	return fmt.Sprintf("{\"Arc\":[%v,%v,%v,%v,%v]}", a.x, a.y, a.r, a.start, a.start+a.sweep)
+}
+func (a Arc) String() string {
+	return fmt.Sprintf("Arc(%s, %s, r=%s, start=%s, end=%s)", readable(a.x), readable(a.y), readable(a.r), readable(a.start), readable(a.start+a.sweep))
+}
+func (a Arc) At(t float64) Point {
+	sin, cos := math.Sincos(a.start + t*a.sweep)
+	return Point{a.x + a.r*cos, a.y + a.r*sin}
+}
+func (a Arc) Bounds() (float64, float64, float64, float64) {
+	s, e := a.At(0), a.At(1)
+	minX, minY := math.Min(s.x, e.x), math.Min(s.y, e.y)
+	maxX, maxY := math.Max(s.x, e.x), math.Max(s.y, e.y)
+	// the extreme points of the circle lie at quarter turns
+	for k := 0; k < 4; k++ {
+		phi := float64(k) * math.Pi / 2
+		if normalizeAngle(phi-a.start) <= a.sweep {
+			p := Point{a.x + a.r*math.Cos(phi), a.y + a.r*math.Sin(phi)}
+			minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+			maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+		}
+	}
+	return minX, minY, maxX, maxY
+}
+func (a Arc) Split(t float64) (Curve, Curve) {
+	return Arc{a.x, a.y, a.r, a.start, t * a.sweep},
+		Arc{a.x, a.y, a.r, normalizeAngle(a.start + t*a.sweep), (1 - t) * a.sweep}
+}
+
+// toBeziers approximates a by cubic Beziers over at most an eighth of a
+// turn each, which stay within 5e-6*r of the circle.
+func (a Arc) toBeziers() compoundCurve {
+	n := int(math.Ceil(a.sweep / (math.Pi / 4)))
+	delta := a.sweep / float64(n)
+	// length of the tangent handles
+	k := 4.0 / 3.0 * math.Tan(delta/4) * a.r
+	parts := make([]Curve, n)
+	for i := range parts {
+		phi0 := a.start + float64(i)*delta
+		sin0, cos0 := math.Sincos(phi0)
+		sin1, cos1 := math.Sincos(phi0 + delta)
+		p0 := Point{a.x + a.r*cos0, a.y + a.r*sin0}
+		p3 := Point{a.x + a.r*cos1, a.y + a.r*sin1}
+		parts[i] = Bezier{[]Point{p0, {p0.x - k*sin0, p0.y + k*cos0}, {p3.x + k*sin1, p3.y - k*cos1}, p3}}
+	}
+	return compoundCurve{parts}
+}
+
+// covers reports whether p, assumed on the circle of a, lies on a.
+func (a Arc) covers(p Point) bool {
+	d := normalizeAngle(math.Atan2(p.y-a.y, p.x-a.x) - a.start)
+	return d*a.r <= a.sweep*a.r+epsilon || (2*math.Pi-d)*a.r < epsilon
+}
+
+// clip keeps the points of v, found on the circle of a, that lie on a.
+func (a Arc) clip(v Value) Value {
+	switch vt := v.(type) {
+	case Point:
+		if a.covers(vt) {
+			return vt
+		}
+		return Nowhere
+	case pointSet:
+		var pts []Point
+		for _, p := range vt.pts {
+			if a.covers(p) {
+				pts = append(pts, p)
+			}
+		}
+		return newPointSet(pts)
+	}
+	return v
+}
+
+// overlap intersects two arcs of the same circle.
+func (a Arc) overlap(b Arc) Value {
+	var parts []Value
+	// b as seen from the start of a, once as is and once a full turn back
+	o := normalizeAngle(b.start - a.start)
+	for _, from := range []float64{o, o - 2*math.Pi} {
+		lo, hi := math.Max(from, 0), math.Min(from+b.sweep, a.sweep)
+		if (hi-lo)*a.r > -epsilon {
+			parts = append(parts, NewArc(a.x, a.y, a.r, a.start+lo, a.start+math.Max(lo, hi)))
+		}
+	}
+	return newCollection(parts)
+}
+
+// normalizeAngle maps angle into [0, 2pi).
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle < 0 {
+		angle = angle + 2*math.Pi
+	}
+	// adding zero turns -0 into 0
+	return angle + 0
+}
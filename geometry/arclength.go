@@ -0,0 +1,67 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// AtLength returns the point at arc length s from the start of c. Curved
+// pieces are measured along a flattened copy, so the point lies within
+// flattenTolerance of c.
+func AtLength(c Curve, s float64) (Point, error) {
+	pts := curvePoints(c)
+	walked := 0.0
+	for i := 1; i < len(pts); i++ {
+		step := math.Hypot(pts[i].x-pts[i-1].x, pts[i].y-pts[i-1].y)
+		if s <= walked+step && s >= 0 {
+			if step == 0 {
+				return pts[i], nil
+			}
+			return lerp(pts[i-1], pts[i], (s-walked)/step), nil
+		}
+		walked += step
+	}
+	if s >= 0 && realClose(s, walked) {
+		return pts[len(pts)-1], nil
+	}
+	return Point{}, fmt.Errorf("arc length %v is outside the curve of length %v", s, walked)
+}
+
+// LengthTable returns the arc length from the start of c to At(i/n) for i
+// from 0 to n, e.g. to look up the parameter belonging to a length.
+func LengthTable(c Curve, n int) []float64 {
+	if n < 1 {
+		panic("LengthTable needs at least one step")
+	}
+	table := make([]float64, n+1)
+	for i := 1; i < n; i++ {
+		left, _ := c.Split(float64(i) / float64(n))
+		table[i] = curveLength(left)
+	}
+	table[n] = curveLength(c)
+	return table
+}
@@ -0,0 +1,328 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maximum number of halvings when flattening or intersecting a Bézier
+const bezierMaxDepth = 32
+
+// Bezier is a quadratic (3 control points) or cubic (4 control points)
+// Bézier curve.
+type Bezier struct {
+	pts []Point
+}
+
+/* bezier */
+func NewQuadraticBezier(x0 float64, y0 float64, x1 float64, y1 float64, x2 float64, y2 float64) Value {
+	return newBezier([]Point{{x0, y0}, {x1, y1}, {x2, y2}})
+}
+func NewCubicBezier(x0 float64, y0 float64, x1 float64, y1 float64, x2 float64, y2 float64, x3 float64, y3 float64) Value {
+	return newBezier([]Point{{x0, y0}, {x1, y1}, {x2, y2}, {x3, y3}})
+}
+func newBezier(pts []Point) Value {
+	for _, p := range pts[1:] {
+		if !realClose(p.x, pts[0].x) || !realClose(p.y, pts[0].y) {
+			return Bezier{pts}
+		}
+	}
+	return pts[0]
+}
+func (b Bezier) shift(dx float64, dy float64) Value {
+	pts := make([]Point, len(b.pts))
+	for i, p := range b.pts {
+		pts[i] = Point{p.x + dx, p.y + dy}
+	}
+	return Bezier{pts}
+}
+func (b Bezier) mirror(fx float64, fy float64) Value {
+	return Bezier{mapPoints(b.pts, func(p Point) Point { return Point{fx * p.x, fy * p.y} })}
+}
+func (b Bezier) scale(sx float64, sy float64) Value {
+	return Bezier{mapPoints(b.pts, func(p Point) Point { return Point{sx * p.x, sy * p.y} })}
+}
+func (b Bezier) rotate(theta float64) Value {
+	return Bezier{mapPoints(b.pts, func(p Point) Point { return p.rotate(theta).(Point) })}
+}
+func (b Bezier) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return b
+	case Bezier:
+		if b.equal(ot) {
+			return b
+		}
+		return newPointSet(b.intersections(ot, 0, nil))
+	case Line:
+		return newPointSet(curveLineIntersections(b, ot))
+	case Point, LineSegment, Circle:
+		return newPointSet(b.intersections(ot, 0, nil))
+	case pointSet, compoundCurve, Path, Arc, Ray, Polygon, Rect, Triangle, collection:
+		return ot.intersect(b)
+	}
+	panic("Should never been reached")
+}
+func (b Bezier) GoString() string {
+	s := make([]string, 0, 2*len(b.pts))
+	for _, p := range b.pts {
+		s = append(s, fmt.Sprint(p.x), fmt.Sprint(p.y))
+	}
+	if len(b.pts) == 3 {
+		return fmt.Sprintf("{\"QuadraticBezier\":[%s]}", strings.Join(s, ","))
+	}
+	return fmt.Sprintf("{\"CubicBezier\":[%s]}", strings.Join(s, ","))
+}
+func (b Bezier) String() string {
+	if len(b.pts) == 3 {
+		return "QuadraticBezier" + readablePoints(b.pts)
+	}
+	return "CubicBezier" + readablePoints(b.pts)
+}
+
+/* bezier as Curve */
+func (b Bezier) At(t float64) Point {
+	pts := append([]Point(nil), b.pts...)
+	for n := len(pts) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			pts[i] = lerp(pts[i], pts[i+1], t)
+		}
+	}
+	return pts[0]
+}
+func (b Bezier) Bounds() (float64, float64, float64, float64) {
+	// the curve is extremal at its end points or where a derivative is zero
+	ts := []float64{0, 1}
+	for _, coord := range []func(Point) float64{
+		func(p Point) float64 { return p.x },
+		func(p Point) float64 { return p.y },
+	} {
+		c := make([]float64, len(b.pts))
+		for i, p := range b.pts {
+			c[i] = coord(p)
+		}
+		ts = append(ts, b.derivativeRoots(c)...)
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, t := range ts {
+		if t < 0 || t > 1 {
+			continue
+		}
+		p := b.At(t)
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+	return minX, minY, maxX, maxY
+}
+func (b Bezier) Split(t float64) (Curve, Curve) {
+	l, r := b.split(t)
+	return l, r
+}
+
+// Flatten approximates b by a polyline whose distance from the curve is at
+// most tol. The result starts and ends at the curve's end points.
+func (b Bezier) Flatten(tol float64) []Point {
+	if tol < epsilon {
+		tol = epsilon
+	}
+	return b.flatten(tol, []Point{b.pts[0]}, 0)
+}
+
+func (b Bezier) flatten(tol float64, pts []Point, depth int) []Point {
+	if depth >= bezierMaxDepth || b.flatness() <= tol {
+		return append(pts, b.pts[len(b.pts)-1])
+	}
+	l, r := b.split(0.5)
+	return r.flatten(tol, l.flatten(tol, pts, depth+1), depth+1)
+}
+
+// split divides b at t using de Casteljau's algorithm.
+func (b Bezier) split(t float64) (Bezier, Bezier) {
+	n := len(b.pts)
+	left := make([]Point, n)
+	right := make([]Point, n)
+	pts := append([]Point(nil), b.pts...)
+	for k := 0; k < n; k++ {
+		left[k] = pts[0]
+		right[n-1-k] = pts[n-1-k]
+		for i := 0; i < n-1-k; i++ {
+			pts[i] = lerp(pts[i], pts[i+1], t)
+		}
+	}
+	return Bezier{left}, Bezier{right}
+}
+
+// derivativeRoots returns the parameters where the derivative of the
+// one-dimensional Bézier with coefficients c vanishes.
+func (b Bezier) derivativeRoots(c []float64) []float64 {
+	if len(c) == 3 {
+		den := c[0] - 2*c[1] + c[2]
+		if den == 0 {
+			return nil
+		}
+		return []float64{(c[0] - c[1]) / den}
+	}
+	// derivative / 3 = qa*t^2 + qb*t + qc
+	qa := -c[0] + 3*c[1] - 3*c[2] + c[3]
+	qb := 2 * (c[0] - 2*c[1] + c[2])
+	qc := c[1] - c[0]
+	if qa == 0 {
+		if qb == 0 {
+			return nil
+		}
+		return []float64{-qc / qb}
+	}
+	disc := qb*qb - 4*qa*qc
+	if disc < 0 {
+		return nil
+	}
+	sq := math.Sqrt(disc)
+	return []float64{(-qb + sq) / (2 * qa), (-qb - sq) / (2 * qa)}
+}
+
+// flatness is the largest distance of a control point from the chord.
+func (b Bezier) flatness() float64 {
+	p0, pn := b.pts[0], b.pts[len(b.pts)-1]
+	dx, dy := pn.x-p0.x, pn.y-p0.y
+	length := math.Hypot(dx, dy)
+	result := 0.0
+	for _, p := range b.pts[1 : len(b.pts)-1] {
+		var d float64
+		if length == 0 {
+			d = math.Hypot(p.x-p0.x, p.y-p0.y)
+		} else {
+			d = math.Abs(dx*(p.y-p0.y)-dy*(p.x-p0.x)) / length
+		}
+		result = math.Max(result, d)
+	}
+	return result
+}
+
+// hull returns the bounding box of the control points, which contains the
+// curve and is cheaper to compute than Bounds.
+func (b Bezier) hull() (float64, float64, float64, float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range b.pts {
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// mayTouch reports whether the control polygon of b comes close to other.
+func (b Bezier) mayTouch(other Value) bool {
+	minX, minY, maxX, maxY := b.hull()
+	switch ot := other.(type) {
+	case Point:
+		return between(minX, ot.x, maxX) && between(minY, ot.y, maxY)
+	case Line:
+		return b.straddles(ot)
+	case LineSegment:
+		oMinX, oMinY, oMaxX, oMaxY := ot.Bounds()
+		return minX-epsilon < oMaxX && oMinX < maxX+epsilon && minY-epsilon < oMaxY && oMinY < maxY+epsilon &&
+			b.straddles(ot.toLine())
+	case Bezier:
+		oMinX, oMinY, oMaxX, oMaxY := ot.hull()
+		return minX-epsilon < oMaxX && oMinX < maxX+epsilon && minY-epsilon < oMaxY && oMinY < maxY+epsilon
+	case Circle:
+		if minX-epsilon > ot.x+ot.r || ot.x-ot.r > maxX+epsilon || minY-epsilon > ot.y+ot.r || ot.y-ot.r > maxY+epsilon {
+			return false
+		}
+		// the hull cannot reach the circumference if it lies inside it
+		for _, p := range b.pts {
+			if math.Hypot(p.x-ot.x, p.y-ot.y) > ot.r-epsilon {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// straddles reports whether the control points do not all lie strictly on
+// the same side of ln.
+func (b Bezier) straddles(ln Line) bool {
+	above, below := false, false
+	for _, p := range b.pts {
+		d := ln.sin*p.x + ln.cos*p.y - ln.d
+		above = above || d > -epsilon
+		below = below || d < epsilon
+	}
+	return above && below
+}
+
+// intersections subdivides b until its pieces are flat and intersects their
+// chords with other.
+func (b Bezier) intersections(other Value, depth int, pts []Point) []Point {
+	if !b.mayTouch(other) {
+		return pts
+	}
+	if depth < bezierMaxDepth && b.flatness() >= epsilon {
+		l, r := b.split(0.5)
+		return r.intersections(other, depth+1, l.intersections(other, depth+1, pts))
+	}
+	p0, pn := b.pts[0], b.pts[len(b.pts)-1]
+	chord := NewLineSegment(p0.x, p0.y, pn.x, pn.y)
+	if ot, ok := other.(Bezier); ok {
+		return ot.intersections(chord, 0, pts)
+	}
+	switch r := chord.intersect(other).(type) {
+	case Point:
+		pts = addPoint(pts, r)
+	case pointSet:
+		for _, p := range r.pts {
+			pts = addPoint(pts, p)
+		}
+	case LineSegment:
+		// a flat piece running along other is reported by its end points
+		pts = addPoint(addPoint(pts, Point{r.x1, r.y1}), Point{r.x2, r.y2})
+	}
+	return pts
+}
+
+func (b Bezier) equal(other Bezier) bool {
+	if len(b.pts) != len(other.pts) {
+		return false
+	}
+	n := len(b.pts)
+	forward, backward := true, true
+	for i := range b.pts {
+		p, q, r := b.pts[i], other.pts[i], other.pts[n-1-i]
+		forward = forward && realClose(p.x, q.x) && realClose(p.y, q.y)
+		backward = backward && realClose(p.x, r.x) && realClose(p.y, r.y)
+	}
+	return forward || backward
+}
+
+func lerp(p Point, q Point, t float64) Point {
+	return Point{p.x + t*(q.x-p.x), p.y + t*(q.y-p.y)}
+}
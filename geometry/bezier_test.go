@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBezierIntersectCircle(t *testing.T) {
+	ex, ey := math.Sqrt(5.0/3), math.Sqrt(7.0/12)
+	cases := []struct {
+		name  string
+		curve Value
+		other Value
+		want  []Point
+	}{
+		{"flat cubic through a circle", NewCubicBezier(-2, 0, -1, 0, 1, 0, 2, 0), NewCircle(0, 0, 1),
+			[]Point{{-1, 0}, {1, 0}}},
+		{"flat quadratic through a circle", NewQuadraticBezier(0, -3, 0, 0, 0, 3), NewCircle(0, 1, 1),
+			[]Point{{0, 0}, {0, 2}}},
+		{"flat cubic ending inside", NewCubicBezier(-2, 0, -1, 0, 0, 0, 0.5, 0), NewCircle(0, 0, 1),
+			[]Point{{-1, 0}}},
+		{"cubic arch over a circle", NewCubicBezier(-3, 0, -1, 4, 1, 4, 3, 0), NewCircle(0, 0, 1),
+			nil},
+		{"ellipse through a circle", Scale(2, 1, NewCircle(0, 0, 1)), NewCircle(0, 0, 1.5),
+			[]Point{{ex, ey}, {-ex, ey}, {-ex, -ey}, {ex, -ey}}},
+	}
+	for _, c := range cases {
+		for _, r := range []Value{Intersect(c.curve, c.other), Intersect(c.other, c.curve)} {
+			got := pointsOf(r)
+			if len(got) != len(c.want) || (len(c.want) == 0 && r != Nowhere) {
+				t.Errorf("%s: got %#v, want %v", c.name, r, c.want)
+				continue
+			}
+			for _, w := range c.want {
+				found := false
+				for _, p := range got {
+					found = found || math.Hypot(p.x-w.x, p.y-w.y) < 1e-4
+				}
+				if !found {
+					t.Errorf("%s: %v missing from %#v", c.name, w, r)
+				}
+			}
+		}
+	}
+}
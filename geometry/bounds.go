@@ -0,0 +1,74 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import "math"
+
+// Bounds returns the axis-aligned bounding box of gv, which may be
+// degenerate, and false for lines, rays, Everywhere and Nowhere.
+func Bounds(gv Value) (Rect, bool) {
+	minX, minY, maxX, maxY, ok := valueBounds(gv)
+	if !ok {
+		return Rect{}, false
+	}
+	return Rect{minX, minY, maxX, maxY}, true
+}
+
+// valueBounds returns the bounding box of gv. The last result is false for
+// unbounded values and for Nowhere.
+func valueBounds(gv Value) (float64, float64, float64, float64, bool) {
+	switch v := gv.(type) {
+	case Point:
+		return v.x, v.y, v.x, v.y, true
+	case pointSet:
+		minX, minY, maxX, maxY := Path{v.pts}.Bounds()
+		return minX, minY, maxX, maxY, true
+	case Curve:
+		minX, minY, maxX, maxY := v.Bounds()
+		return minX, minY, maxX, maxY, true
+	case Circle:
+		return v.x - v.r, v.y - v.r, v.x + v.r, v.y + v.r, true
+	case Polygon:
+		minX, minY, maxX, maxY := v.boundary().Bounds()
+		return minX, minY, maxX, maxY, true
+	case Rect:
+		return v.minX, v.minY, v.maxX, v.maxY, true
+	case Triangle:
+		return valueBounds(v.toPolygon())
+	case collection:
+		minX, minY := math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for _, m := range v.vs {
+			mMinX, mMinY, mMaxX, mMaxY, ok := valueBounds(m)
+			if !ok {
+				return mMinX, mMinY, mMaxX, mMaxY, false
+			}
+			minX, minY = math.Min(minX, mMinX), math.Min(minY, mMinY)
+			maxX, maxY = math.Max(maxX, mMaxX), math.Max(maxY, mMaxY)
+		}
+		return minX, minY, maxX, maxY, true
+	}
+	return math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1), false
+}
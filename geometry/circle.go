@@ -0,0 +1,128 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Circle is the circumference of a circle, not the disc inside it.
+type Circle struct {
+	x float64
+	y float64
+	r float64
+}
+
+/* circle */
+func NewCircle(x float64, y float64, r float64) Value {
+	if r < 0 {
+		panic("Negative radius")
+	} else if realClose(r, 0) {
+		return Point{x, y}
+	}
+	return Circle{x, y, r}
+}
+func (c Circle) shift(dx float64, dy float64) Value {
+	return Circle{c.x + dx, c.y + dy, c.r}
+}
+func (c Circle) mirror(fx float64, fy float64) Value {
+	return Circle{fx * c.x, fy * c.y, c.r}
+}
+func (c Circle) scale(sx float64, sy float64) Value {
+	if !realClose(math.Abs(sx), math.Abs(sy)) {
+		// an ellipse, which Beziers can follow
+		return Arc{c.x, c.y, c.r, 0, 2 * math.Pi}.toBeziers().scale(sx, sy)
+	}
+	return Circle{sx * c.x, sy * c.y, math.Abs(sx) * c.r}
+}
+func (c Circle) rotate(theta float64) Value {
+	p := Point{c.x, c.y}.rotate(theta).(Point)
+	return Circle{p.x, p.y, c.r}
+}
+func (c Circle) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return c
+	case Point:
+		if realClose(math.Hypot(ot.x-c.x, ot.y-c.y), c.r) {
+			return ot
+		} else {
+			return Nowhere
+		}
+	case Line:
+		return newPointSet(c.lineIntersections(ot))
+	case LineSegment:
+		var pts []Point
+		for _, p := range c.lineIntersections(ot.toLine()) {
+			if between(ot.x1, p.x, ot.x2) && between(ot.y1, p.y, ot.y2) {
+				pts = append(pts, p)
+			}
+		}
+		return newPointSet(pts)
+	case Circle:
+		d := math.Hypot(ot.x-c.x, ot.y-c.y)
+		if realClose(d, 0) {
+			if realClose(c.r, ot.r) {
+				return c
+			} else {
+				return Nowhere
+			}
+		}
+		if d > c.r+ot.r+epsilon || d < math.Abs(c.r-ot.r)-epsilon {
+			return Nowhere
+		}
+		// a is the distance from c's center to the chord through both points
+		a := (d*d + c.r*c.r - ot.r*ot.r) / (2 * d)
+		h := math.Sqrt(math.Max(c.r*c.r-a*a, 0))
+		ux, uy := (ot.x-c.x)/d, (ot.y-c.y)/d
+		mx, my := c.x+a*ux, c.y+a*uy
+		return newPointSet([]Point{{mx - h*uy, my + h*ux}, {mx + h*uy, my - h*ux}})
+	case Bezier, pointSet, compoundCurve, Path, Arc, Ray, Polygon, Rect, Triangle, collection:
+		return ot.intersect(c)
+	}
+	panic("Should never been reached")
+}
+func (c Circle) GoString() string {
+	return fmt.Sprintf("{\"Circle\":[%v,%v,%v]}", c.x, c.y, c.r)
+}
+func (c Circle) String() string {
+	return fmt.Sprintf("Circle(%s, %s, r=%s)", readable(c.x), readable(c.y), readable(c.r))
+}
+
+// lineIntersections returns the zero, one or two points where ln meets c.
+func (c Circle) lineIntersections(ln Line) []Point {
+	sin, cos := ln.sin, ln.cos
+	// signed distance of the center from the line
+	h := sin*c.x + cos*c.y - ln.d
+	if math.Abs(h) > c.r+epsilon {
+		return nil
+	}
+	fx, fy := c.x-h*sin, c.y-h*cos
+	s := math.Sqrt(math.Max(c.r*c.r-h*h, 0))
+	return []Point{{fx + s*cos, fy - s*sin}, {fx - s*cos, fy + s*sin}}
+}
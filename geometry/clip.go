@@ -0,0 +1,263 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// The boolean operations split the edges of both polygons at their mutual
+// intersections, keep the pieces selected by the operation and link them
+// into rings. Each resulting region is a counterclockwise outer ring with
+// the clockwise rings of its holes.
+
+// PolygonWithHoles is the region inside Outer but not inside any of Holes.
+type PolygonWithHoles struct {
+	Outer Polygon
+	Holes []Polygon
+}
+
+// PolygonIntersection returns the regions covered by both a and b.
+func PolygonIntersection(a Polygon, b Polygon) []PolygonWithHoles {
+	return groupHoles(clip(a, b, clipIntersection))
+}
+
+// PolygonUnion returns the regions covered by a or b.
+func PolygonUnion(a Polygon, b Polygon) []PolygonWithHoles {
+	return groupHoles(clip(a, b, clipUnion))
+}
+
+// PolygonDifference returns the regions covered by a but not by b.
+func PolygonDifference(a Polygon, b Polygon) []PolygonWithHoles {
+	return groupHoles(clip(a, b, clipDifference))
+}
+
+// PolygonXor returns the regions covered by exactly one of a and b.
+func PolygonXor(a Polygon, b Polygon) []PolygonWithHoles {
+	return append(PolygonDifference(a, b), PolygonDifference(b, a)...)
+}
+
+// groupHoles puts every clockwise ring with the smallest counterclockwise
+// ring around it.
+func groupHoles(rings []Polygon) []PolygonWithHoles {
+	var result []PolygonWithHoles
+	var holes []Polygon
+	for _, pg := range rings {
+		if pg.area() > 0 {
+			result = append(result, PolygonWithHoles{Outer: pg})
+		} else {
+			holes = append(holes, pg)
+		}
+	}
+	for _, h := range holes {
+		best := -1
+		for i, r := range result {
+			if r.Outer.area() < -h.area() || (best >= 0 && r.Outer.area() >= result[best].Outer.area()) {
+				continue
+			}
+			// a vertex may lie on the outer ring, the middle of an edge
+			// then lies inside
+			for k := range h.pts {
+				a, b := h.edge(k)
+				if r.Outer.inside(a) || r.Outer.inside(lerp(a, b, 0.5)) {
+					best = i
+					break
+				}
+			}
+		}
+		if best >= 0 {
+			result[best].Holes = append(result[best].Holes, h)
+		}
+	}
+	return result
+}
+
+type clipOp int
+
+const (
+	clipIntersection clipOp = iota
+	clipUnion
+	clipDifference
+)
+
+// position of an edge piece relative to the other polygon
+type piecePosition int
+
+const (
+	pieceInside piecePosition = iota
+	pieceOutside
+	pieceSharedSame     // on an edge of the other polygon, same direction
+	pieceSharedOpposite // on an edge of the other polygon, opposite direction
+)
+
+type piece struct {
+	from Point
+	to   Point
+	pos  piecePosition
+}
+
+func clip(a Polygon, b Polygon, op clipOp) []Polygon {
+	a, b = a.counterclockwise(), b.counterclockwise()
+	piecesA, piecesB := splitEdges(a, b)
+	var edges []piece
+	for _, pc := range piecesA {
+		switch {
+		case pc.pos == pieceInside && op == clipIntersection,
+			pc.pos == pieceOutside && op != clipIntersection,
+			pc.pos == pieceSharedSame && op != clipDifference,
+			pc.pos == pieceSharedOpposite && op == clipDifference:
+			edges = append(edges, pc)
+		}
+	}
+	for _, pc := range piecesB {
+		switch {
+		case pc.pos == pieceInside && op == clipIntersection,
+			pc.pos == pieceOutside && op == clipUnion:
+			edges = append(edges, pc)
+		case pc.pos == pieceInside && op == clipDifference:
+			edges = append(edges, piece{pc.to, pc.from, pc.pos})
+		}
+	}
+	return linkRings(edges)
+}
+
+// splitEdges cuts the edges of a and b at every point where they meet and
+// classifies the pieces against the other polygon.
+func splitEdges(a Polygon, b Polygon) ([]piece, []piece) {
+	cutsA := make([][]Point, len(a.pts))
+	cutsB := make([][]Point, len(b.pts))
+	for i := range a.pts {
+		p1, p2 := a.edge(i)
+		for j := range b.pts {
+			q1, q2 := b.edge(j)
+			var cuts []Point
+			switch r := NewLineSegment(p1.x, p1.y, p2.x, p2.y).intersect(NewLineSegment(q1.x, q1.y, q2.x, q2.y)).(type) {
+			case Point:
+				cuts = []Point{r}
+			case LineSegment:
+				cuts = []Point{{r.x1, r.y1}, {r.x2, r.y2}}
+			}
+			// prefer exact vertices over recomputed intersection points
+			for k := range cuts {
+				for _, v := range []Point{p1, p2, q1, q2} {
+					if realClosePoint(cuts[k], v) {
+						cuts[k] = v
+					}
+				}
+			}
+			cutsA[i] = append(cutsA[i], cuts...)
+			cutsB[j] = append(cutsB[j], cuts...)
+		}
+	}
+	return classifyPieces(a, cutsA, b), classifyPieces(b, cutsB, a)
+}
+
+func classifyPieces(pg Polygon, cuts [][]Point, other Polygon) []piece {
+	var result []piece
+	for i := range pg.pts {
+		from, to := pg.edge(i)
+		pts := append([]Point{from, to}, cuts[i]...)
+		// order the cuts along the edge
+		sort.Slice(pts, func(k, l int) bool {
+			return (pts[k].x-from.x)*(to.x-from.x)+(pts[k].y-from.y)*(to.y-from.y) <
+				(pts[l].x-from.x)*(to.x-from.x)+(pts[l].y-from.y)*(to.y-from.y)
+		})
+		for k := 1; k < len(pts); k++ {
+			p, q := pts[k-1], pts[k]
+			if realClosePoint(p, q) {
+				continue
+			}
+			mid := lerp(p, q, 0.5)
+			pos := pieceOutside
+			if other.onBoundary(mid) {
+				pos = pieceSharedOpposite
+				for j := range other.pts {
+					o1, o2 := other.edge(j)
+					if (o2.x-o1.x)*(q.x-p.x)+(o2.y-o1.y)*(q.y-p.y) > 0 {
+						if _, ok := NewLineSegment(o1.x, o1.y, o2.x, o2.y).intersect(mid).(Point); ok {
+							pos = pieceSharedSame
+						}
+					}
+				}
+			} else if other.inside(mid) {
+				pos = pieceInside
+			}
+			result = append(result, piece{p, q, pos})
+		}
+	}
+	return result
+}
+
+// linkRings joins directed edges end to start into closed rings. Where
+// several edges leave the same point the one turning furthest left is
+// taken, which keeps rings that only touch in a point apart.
+func linkRings(edges []piece) []Polygon {
+	used := make([]bool, len(edges))
+	var result []Polygon
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		ring := []Point{edges[start].from}
+		cur := edges[start]
+		for !realClosePoint(cur.to, edges[start].from) {
+			next := -1
+			best := math.Inf(-1)
+			inAngle := math.Atan2(cur.to.y-cur.from.y, cur.to.x-cur.from.x)
+			for k, e := range edges {
+				if used[k] || !realClosePoint(e.from, cur.to) {
+					continue
+				}
+				turn := math.Atan2(e.to.y-e.from.y, e.to.x-e.from.x) - inAngle
+				// normalise to (-pi, pi], left turns are positive
+				for turn <= -math.Pi {
+					turn += 2 * math.Pi
+				}
+				for turn > math.Pi {
+					turn -= 2 * math.Pi
+				}
+				if turn > best {
+					next, best = k, turn
+				}
+			}
+			if next < 0 {
+				break // open chain, only possible through rounding trouble
+			}
+			used[next] = true
+			ring = append(ring, edges[next].from)
+			cur = edges[next]
+		}
+		for i, p := range ring {
+			// adding zero turns -0 into 0
+			ring[i] = Point{p.x + 0, p.y + 0}
+		}
+		if ring = removeCollinear(ring); len(ring) >= 3 {
+			result = append(result, Polygon{ring})
+		}
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// ClipToConvex cuts points, lines, rays, segments and collections of them down to
+// the parts inside the convex polygon hull or on its boundary, clipping
+// against the half-plane of one edge after the other.
+func ClipToConvex(v Value, hull Polygon) Value {
+	hull = hull.counterclockwise()
+	for i := range hull.pts {
+		a, b := hull.edge(i)
+		_, c := hull.edge((i + 1) % len(hull.pts))
+		if (b.x-a.x)*(c.y-a.y)-(b.y-a.y)*(c.x-a.x) < -epsilon {
+			panic("ClipToConvex needs a convex Polygon")
+		}
+	}
+	switch vt := v.(type) {
+	case nowhere:
+		return Nowhere
+	case Point:
+		if _, _, ok := hull.clipRange(vt, Point{0, 0}, 0, 0); ok {
+			return vt
+		}
+		return Nowhere
+	case pointSet:
+		var pts []Point
+		for _, p := range vt.pts {
+			if _, ok := ClipToConvex(p, hull).(Point); ok {
+				pts = append(pts, p)
+			}
+		}
+		return newPointSet(pts)
+	case collection:
+		return vt.each(func(m Value) Value { return ClipToConvex(m, hull) })
+	case Line:
+		sin, cos := vt.sin, vt.cos
+		return hull.clipSegment(Point{vt.d * sin, vt.d * cos}, Point{cos, -sin}, math.Inf(-1), math.Inf(1))
+	case Ray:
+		sin, cos := math.Sincos(vt.angle)
+		return hull.clipSegment(Point{vt.x, vt.y}, Point{cos, sin}, 0, math.Inf(1))
+	case LineSegment:
+		return hull.clipSegment(Point{vt.x1, vt.y1}, Point{vt.x2 - vt.x1, vt.y2 - vt.y1}, 0, 1)
+	}
+	panic("ClipToConvex expects a Point, PointSet, Line, Ray, LineSegment or Collection")
+}
+
+// clipRange narrows the parameters [tmin, tmax] of the points o + t*dir to
+// those inside the convex, counterclockwise pg. ok is false if none is.
+func (pg Polygon) clipRange(o Point, dir Point, tmin float64, tmax float64) (float64, float64, bool) {
+	for i := range pg.pts {
+		a, b := pg.edge(i)
+		ex, ey := b.x-a.x, b.y-a.y
+		// o + t*dir is inside the edge's half-plane for f0 + t*f1 >= 0
+		f0 := ex*(o.y-a.y) - ey*(o.x-a.x)
+		f1 := ex*dir.y - ey*dir.x
+		switch {
+		case f1 > 0:
+			tmin = math.Max(tmin, -f0/f1)
+		case f1 < 0:
+			tmax = math.Min(tmax, -f0/f1)
+		case f0 < -epsilon*math.Hypot(ex, ey):
+			return 0, 0, false
+		}
+	}
+	if tmin > tmax {
+		// a range shorter than epsilon is a single touching point
+		if (tmin-tmax)*math.Hypot(dir.x, dir.y) > epsilon {
+			return 0, 0, false
+		}
+		tmin = (tmin + tmax) / 2
+		tmax = tmin
+	}
+	return tmin, tmax, true
+}
+func (pg Polygon) clipSegment(o Point, dir Point, tmin float64, tmax float64) Value {
+	tmin, tmax, ok := pg.clipRange(o, dir, tmin, tmax)
+	if !ok {
+		return Nowhere
+	}
+	return NewLineSegment(o.x+tmin*dir.x, o.y+tmin*dir.y, o.x+tmax*dir.x, o.y+tmax*dir.y)
+}
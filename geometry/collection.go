@@ -0,0 +1,140 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// collection holds the components of a value made of several pieces of
+// different kinds, e.g. the segments and single points where a line meets a
+// polygon. A value made of points only is a pointSet instead.
+type collection struct {
+	vs []Value
+}
+
+/* collection */
+func NewCollection(vs ...Value) Value {
+	return newCollection(vs)
+}
+
+// newCollection makes the simplest value covering all of vs: nested
+// collections are flattened, Nowhere is dropped and points already covered
+// by another member are left out.
+func newCollection(vs []Value) Value {
+	var flat []Value
+	for _, v := range vs {
+		switch vt := v.(type) {
+		case nowhere:
+		case everywhere:
+			return Everywhere
+		case collection:
+			flat = append(flat, vt.vs...)
+		case pointSet:
+			for _, p := range vt.pts {
+				flat = append(flat, p)
+			}
+		default:
+			flat = append(flat, vt)
+		}
+	}
+	var pts []Point
+	var others []Value
+	for _, v := range flat {
+		if p, ok := v.(Point); ok {
+			pts = addPoint(pts, p)
+		} else {
+			others = append(others, v)
+		}
+	}
+	if len(others) == 0 {
+		return newPointSet(pts)
+	}
+	result := others
+	for _, p := range pts {
+		covered := false
+		for _, o := range others {
+			if _, ok := o.intersect(p).(Point); ok {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			result = append(result, p)
+		}
+	}
+	if len(result) == 1 {
+		return result[0]
+	}
+	return collection{result}
+}
+func (cl collection) shift(dx float64, dy float64) Value {
+	return cl.each(func(v Value) Value { return v.shift(dx, dy) })
+}
+func (cl collection) mirror(fx float64, fy float64) Value {
+	return cl.each(func(v Value) Value { return v.mirror(fx, fy) })
+}
+func (cl collection) scale(sx float64, sy float64) Value {
+	return cl.each(func(v Value) Value { return v.scale(sx, sy) })
+}
+func (cl collection) rotate(theta float64) Value {
+	return cl.each(func(v Value) Value { return v.rotate(theta) })
+}
+func (cl collection) intersect(other Value) Value {
+	switch other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return cl
+	}
+	return cl.each(func(v Value) Value { return v.intersect(other) })
+}
+func (cl collection) GoString() string {
+	s := make([]string, len(cl.vs))
+	for i, v := range cl.vs {
+		s[i] = v.GoString()
+	}
+	return fmt.Sprintf("{\"Collection\":[%s]}", strings.Join(s, ","))
+}
+func (cl collection) String() string {
+	s := make([]string, len(cl.vs))
+	for i, v := range cl.vs {
+		s[i] = v.String()
+	}
+	return fmt.Sprintf("Collection(%s)", strings.Join(s, ", "))
+}
+func (cl collection) Members() []Value {
+	return append([]Value{}, cl.vs...)
+}
+
+// each applies f to every member and collects the results.
+func (cl collection) each(f func(Value) Value) Value {
+	vs := make([]Value, len(cl.vs))
+	for i, v := range cl.vs {
+		vs[i] = f(v)
+	}
+	return newCollection(vs)
+}
@@ -0,0 +1,180 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// compoundCurve joins curves end to end. The parameter t is spread evenly
+// over the parts.
+type compoundCurve struct {
+	parts []Curve
+}
+
+/* compoundCurve */
+func newCompoundCurve(parts []Curve) Curve {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return compoundCurve{parts}
+}
+func (cc compoundCurve) shift(dx float64, dy float64) Value {
+	parts := make([]Curve, len(cc.parts))
+	for i, c := range cc.parts {
+		parts[i] = c.shift(dx, dy).(Curve)
+	}
+	return compoundCurve{parts}
+}
+func (cc compoundCurve) mirror(fx float64, fy float64) Value {
+	parts := make([]Curve, len(cc.parts))
+	for i, c := range cc.parts {
+		parts[i] = c.mirror(fx, fy).(Curve)
+	}
+	return compoundCurve{parts}
+}
+func (cc compoundCurve) scale(sx float64, sy float64) Value {
+	parts := make([]Curve, len(cc.parts))
+	for i, c := range cc.parts {
+		parts[i] = c.scale(sx, sy).(Curve)
+	}
+	return compoundCurve{parts}
+}
+func (cc compoundCurve) rotate(theta float64) Value {
+	parts := make([]Curve, len(cc.parts))
+	for i, c := range cc.parts {
+		parts[i] = c.rotate(theta).(Curve)
+	}
+	return compoundCurve{parts}
+}
+func (cc compoundCurve) intersect(other Value) Value {
+	switch other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return cc
+	}
+	var parts []Value
+	for _, c := range cc.parts {
+		parts = append(parts, c.intersect(other))
+	}
+	return newCollection(parts)
+}
+func (cc compoundCurve) GoString() string {
+	s := make([]string, len(cc.parts))
+	for i, c := range cc.parts {
+		s[i] = c.GoString()
+	}
+	return fmt.Sprintf("{\"CompoundCurve\":[%s]}", strings.Join(s, ","))
+}
+func (cc compoundCurve) String() string {
+	s := make([]string, len(cc.parts))
+	for i, c := range cc.parts {
+		s[i] = c.String()
+	}
+	return fmt.Sprintf("CompoundCurve(%s)", strings.Join(s, ", "))
+}
+
+/* compoundCurve as Curve */
+func (cc compoundCurve) At(t float64) Point {
+	i, u := cc.locate(t)
+	return cc.parts[i].At(u)
+}
+func (cc compoundCurve) Bounds() (float64, float64, float64, float64) {
+	minX, minY, maxX, maxY := cc.parts[0].Bounds()
+	for _, c := range cc.parts[1:] {
+		x1, y1, x2, y2 := c.Bounds()
+		minX, minY = math.Min(minX, x1), math.Min(minY, y1)
+		maxX, maxY = math.Max(maxX, x2), math.Max(maxY, y2)
+	}
+	return minX, minY, maxX, maxY
+}
+func (cc compoundCurve) Split(t float64) (Curve, Curve) {
+	i, u := cc.locate(t)
+	l, r := cc.parts[i].Split(u)
+	left := append(append([]Curve(nil), cc.parts[:i]...), l)
+	right := append([]Curve{r}, cc.parts[i+1:]...)
+	return newCompoundCurve(left), newCompoundCurve(right)
+}
+
+// locate maps t to the index of a part and the parameter within that part.
+func (cc compoundCurve) locate(t float64) (int, float64) {
+	n := float64(len(cc.parts))
+	i := int(math.Floor(t * n))
+	if i < 0 {
+		i = 0
+	} else if i >= len(cc.parts) {
+		i = len(cc.parts) - 1
+	}
+	return i, t*n - float64(i)
+}
+
+// FitSpline returns a cardinal spline through points made of cubic Bézier
+// pieces. A smoothness of 1 gives a Catmull-Rom spline, 0 the polyline
+// through the points. With fewer than two distinct points it returns the
+// only point or Nowhere.
+func FitSpline(points []Point, smoothness float64) Value {
+	var pts []Point
+	for _, p := range points {
+		if len(pts) == 0 || !realClosePoint(p, pts[len(pts)-1]) {
+			pts = append(pts, p)
+		}
+	}
+	switch len(pts) {
+	case 0:
+		return Nowhere
+	case 1:
+		return pts[0]
+	}
+	// tangent at every point, one-sided at the ends
+	tangents := make([]Point, len(pts))
+	for i := range pts {
+		prev, next := pts[i], pts[i]
+		scale := smoothness
+		if i > 0 {
+			prev = pts[i-1]
+		}
+		if i < len(pts)-1 {
+			next = pts[i+1]
+		}
+		if i > 0 && i < len(pts)-1 {
+			scale = smoothness / 2
+		}
+		tangents[i] = Point{scale * (next.x - prev.x), scale * (next.y - prev.y)}
+	}
+	parts := make([]Curve, len(pts)-1)
+	for i := range parts {
+		p, q := pts[i], pts[i+1]
+		parts[i] = Bezier{[]Point{
+			p,
+			{p.x + tangents[i].x/3, p.y + tangents[i].y/3},
+			{q.x - tangents[i+1].x/3, q.y - tangents[i+1].y/3},
+			q,
+		}}
+	}
+	return newCompoundCurve(parts)
+}
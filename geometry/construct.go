@@ -0,0 +1,67 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// LineFromSlopeIntercept returns the line y = m*x + b. Vertical lines have
+// no slope and must be made with NewLine(math.Pi/2, x).
+func LineFromSlopeIntercept(m float64, b float64) Line {
+	if math.IsInf(m, 0) || math.IsNaN(m) {
+		panic("LineFromSlopeIntercept needs a finite slope")
+	}
+	// -m*x + y = b
+	return lineFromNormal(-m, 1, b)
+}
+
+// ParallelThrough returns the line through p parallel to ln.
+func ParallelThrough(ln Line, p Point) Line {
+	return lineFromNormal(ln.sin, ln.cos, ln.sin*p.x+ln.cos*p.y)
+}
+
+// PerpendicularBisector returns the line of points as far from one end
+// point of ls as from the other.
+func PerpendicularBisector(ls LineSegment) Line {
+	dx, dy := ls.x2-ls.x1, ls.y2-ls.y1
+	m := ls.Midpoint()
+	return lineFromNormal(dx, dy, dx*m.x+dy*m.y)
+}
+
+// AngleBisectors returns the two lines halving the angles between the
+// intersecting lines l1 and l2. The first one halves the angle between
+// their normals (sin(angle), cos(angle)), the second one is perpendicular
+// to it.
+func AngleBisectors(l1 Line, l2 Line) (Line, Line) {
+	if l1.parallel(l2) {
+		panic("AngleBisectors needs intersecting lines")
+	}
+	// points where sin1*x + cos1*y - d1 = ±(sin2*x + cos2*y - d2)
+	bisector := func(sign float64) Line {
+		return lineFromNormal(l1.sin+sign*l2.sin, l1.cos+sign*l2.cos, l1.d+sign*l2.d)
+	}
+	return bisector(1), bisector(-1)
+}
@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// Context carries the tolerance used to compare coordinates. The package
+// functions use DefaultContext, and constructors always collapse shapes
+// smaller than it, e.g. a Circle of tiny radius into a Point.
+type Context struct {
+	// Epsilon is the distance below which coordinates count as equal.
+	Epsilon float64
+	// Relative makes Epsilon a fraction of the largest coordinate of the
+	// values compared, at least 1, instead of an absolute distance.
+	Relative bool
+}
+
+// DefaultContext holds the package tolerance of 0.00001.
+var DefaultContext = Context{Epsilon: epsilon}
+
+// NewContext returns a Context with tolerance eps.
+func NewContext(eps float64) Context {
+	if !(eps > 0) {
+		panic("Tolerance must be positive")
+	}
+	return Context{Epsilon: eps}
+}
+
+// Intersect is Intersect with coordinates compared to within ctx.Epsilon.
+// Tolerances on angles and curve parameters do not depend on the scale of
+// a value and stay as they are.
+func (ctx Context) Intersect(gv1 Value, gv2 Value) Value {
+	f := ctx.factor(gv1, gv2)
+	if f == 1 {
+		return gv1.intersect(gv2)
+	}
+	return gv1.scale(f, f).intersect(gv2.scale(f, f)).scale(1/f, 1/f)
+}
+
+// Equal is ApproxEqual with tolerance ctx.Epsilon.
+func (ctx Context) Equal(a Value, b Value) bool {
+	return approxEqual(a, b, epsilon/ctx.factor(a, b))
+}
+
+// factor returns the scale that turns the tolerance of ctx for vs into
+// the package tolerance.
+func (ctx Context) factor(vs ...Value) float64 {
+	eps := ctx.Epsilon
+	if ctx.Relative {
+		m := 1.0
+		for _, v := range vs {
+			m = math.Max(m, magnitude(v))
+		}
+		eps *= m
+	}
+	return epsilon / eps
+}
+
+// magnitude returns the largest absolute coordinate of gv, for lines the
+// distance from the origin and for rays that of their start.
+func magnitude(gv Value) float64 {
+	switch v := gv.(type) {
+	case Line:
+		return math.Abs(v.d)
+	case Ray:
+		return math.Max(math.Abs(v.x), math.Abs(v.y))
+	case collection:
+		m := 0.0
+		for _, mv := range v.vs {
+			m = math.Max(m, magnitude(mv))
+		}
+		return m
+	}
+	minX, minY, maxX, maxY, ok := valueBounds(gv)
+	if !ok {
+		return 0
+	}
+	return math.Max(math.Max(math.Abs(minX), math.Abs(minY)), math.Max(math.Abs(maxX), math.Abs(maxY)))
+}
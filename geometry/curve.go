@@ -0,0 +1,145 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// number of samples used when searching a curve numerically
+const curveSamples = 64
+
+// Curve is a bounded value parametrised over t in [0,1].
+type Curve interface {
+	Value
+	At(t float64) Point
+	Bounds() (minX float64, minY float64, maxX float64, maxY float64)
+	Split(t float64) (Curve, Curve)
+}
+
+/* lineSegment as Curve */
+func (ls LineSegment) At(t float64) Point {
+	return Point{ls.x1 + t*(ls.x2-ls.x1), ls.y1 + t*(ls.y2-ls.y1)}
+}
+func (ls LineSegment) Bounds() (float64, float64, float64, float64) {
+	return math.Min(ls.x1, ls.x2), math.Min(ls.y1, ls.y2), math.Max(ls.x1, ls.x2), math.Max(ls.y1, ls.y2)
+}
+func (ls LineSegment) Split(t float64) (Curve, Curve) {
+	p := ls.At(t)
+	return LineSegment{ls.x1, ls.y1, p.x, p.y}, LineSegment{p.x, p.y, ls.x2, ls.y2}
+}
+
+// curveLineIntersections finds the points where c meets ln numerically:
+// the signed distance to ln is sampled along c, every sign change is refined
+// by bisection and every sample closer than its neighbours by a search for
+// a point touching ln.
+func curveLineIntersections(c Curve, ln Line) []Point {
+	dist := func(t float64) float64 {
+		p := c.At(t)
+		return ln.sin*p.x + ln.cos*p.y - ln.d
+	}
+	f := make([]float64, curveSamples+1)
+	for i := range f {
+		f[i] = dist(float64(i) / curveSamples)
+	}
+	var result []Point
+	for i := range f {
+		t := float64(i) / curveSamples
+		switch {
+		case realClose(f[i], 0):
+			result = addPoint(result, c.At(t))
+		case i > 0 && !realClose(f[i-1], 0) && (f[i-1] < 0) != (f[i] < 0):
+			lo, hi := t-1.0/curveSamples, t
+			for hi-lo > epsilon*epsilon {
+				mid := (lo + hi) / 2
+				if (dist(mid) < 0) == (f[i-1] < 0) {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			result = addPoint(result, c.At((lo+hi)/2))
+		case i > 0 && i < curveSamples && (f[i-1] < 0) == (f[i] < 0) && (f[i+1] < 0) == (f[i] < 0) &&
+			math.Abs(f[i]) < math.Abs(f[i-1]) && math.Abs(f[i]) < math.Abs(f[i+1]):
+			touch := minimize(func(t float64) float64 { return math.Abs(dist(t)) }, t-1.0/curveSamples, t+1.0/curveSamples)
+			if realClose(dist(touch), 0) {
+				result = addPoint(result, c.At(touch))
+			}
+		}
+	}
+	return result
+}
+
+// curveParam returns the parameter of the point of c closest to p, found by
+// sampling c and refining around the best sample.
+func curveParam(c Curve, p Point) float64 {
+	dist := func(t float64) float64 {
+		q := c.At(t)
+		return math.Hypot(q.x-p.x, q.y-p.y)
+	}
+	best := 0.0
+	for i := 1; i <= curveSamples; i++ {
+		if t := float64(i) / curveSamples; dist(t) < dist(best) {
+			best = t
+		}
+	}
+	return minimize(dist, math.Max(0, best-1.0/curveSamples), math.Min(1, best+1.0/curveSamples))
+}
+
+// minimize returns where f, taken to have a single minimum on [lo, hi], is
+// smallest, by ternary search.
+func minimize(f func(float64) float64, lo float64, hi float64) float64 {
+	for hi-lo > epsilon*epsilon {
+		m1, m2 := lo+(hi-lo)/3, hi-(hi-lo)/3
+		if f(m1) < f(m2) {
+			hi = m2
+		} else {
+			lo = m1
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// subCurve returns the part of c between the parameters t0 < t1.
+func subCurve(c Curve, t0 float64, t1 float64) Curve {
+	if t1 < 1 {
+		c, _ = c.Split(t1)
+	}
+	if t0 > 0 {
+		_, c = c.Split(t0 / t1)
+	}
+	return c
+}
+
+// pointsOf lists the points of a Point or PointSet.
+func pointsOf(v Value) []Point {
+	switch vt := v.(type) {
+	case Point:
+		return []Point{vt}
+	case pointSet:
+		return vt.pts
+	}
+	return nil
+}
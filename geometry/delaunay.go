@@ -0,0 +1,102 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// triangle indices into the point slice, counterclockwise
+type triangleIndex [3]int
+
+// delaunay triangulates pts with the Bowyer-Watson algorithm. The points
+// must be distinct.
+func delaunay(pts []Point) []triangleIndex {
+	if len(pts) < 3 {
+		return nil
+	}
+	// a triangle around all points, appended behind the input points
+	minX, minY, maxX, maxY := Path{pts}.Bounds()
+	size := math.Max(maxX-minX, maxY-minY) + 1
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+	all := append(append([]Point(nil), pts...),
+		Point{cx - 20*size, cy - 10*size}, Point{cx + 20*size, cy - 10*size}, Point{cx, cy + 20*size})
+	n := len(pts)
+	tris := []triangleIndex{{n, n + 1, n + 2}}
+	for i := 0; i < n; i++ {
+		p := all[i]
+		var keep []triangleIndex
+		edges := map[[2]int]int{}
+		for _, t := range tris {
+			c, r := circumcircle(all[t[0]], all[t[1]], all[t[2]])
+			if math.Hypot(p.x-c.x, p.y-c.y) < r {
+				for k := 0; k < 3; k++ {
+					edges[[2]int{t[k], t[(k+1)%3]}]++
+				}
+			} else {
+				keep = append(keep, t)
+			}
+		}
+		// the cavity boundary consists of the edges not shared by two removed
+		// triangles
+		for e := range edges {
+			if edges[[2]int{e[1], e[0]}] == 0 {
+				keep = append(keep, triangleIndex{e[0], e[1], i})
+			}
+		}
+		tris = keep
+	}
+	var result []triangleIndex
+	for _, t := range tris {
+		if t[0] < n && t[1] < n && t[2] < n {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// circumcircle returns the center and radius of the circle through a, b
+// and c. The radius is infinite for collinear points.
+func circumcircle(a Point, b Point, c Point) (Point, float64) {
+	d := 2 * (a.x*(b.y-c.y) + b.x*(c.y-a.y) + c.x*(a.y-b.y))
+	if d == 0 {
+		return a, math.Inf(1)
+	}
+	a2, b2, c2 := a.x*a.x+a.y*a.y, b.x*b.x+b.y*b.y, c.x*c.x+c.y*c.y
+	center := Point{
+		(a2*(b.y-c.y) + b2*(c.y-a.y) + c2*(a.y-b.y)) / d,
+		(a2*(c.x-b.x) + b2*(a.x-c.x) + c2*(b.x-a.x)) / d,
+	}
+	return center, math.Hypot(a.x-center.x, a.y-center.y)
+}
+
+// distinctPoints drops points close to an earlier one.
+func distinctPoints(pts []Point) []Point {
+	var result []Point
+	for _, p := range pts {
+		result = addPoint(result, p)
+	}
+	return result
+}
@@ -0,0 +1,222 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import "math"
+
+// SceneDiff lists how the shapes of one scene changed into another.
+type SceneDiff struct {
+	Added     []Value
+	Removed   []Value
+	Moved     []Move
+	Unchanged int
+}
+
+// Move is a shape found shifted by (Dx, Dy) in the new scene.
+type Move struct {
+	From Value
+	To   Value
+	Dx   float64
+	Dy   float64
+}
+
+// DiffScenes matches the shapes of old and new. Shapes that are ApproxEqual
+// within tol are unchanged, bounded shapes that agree after shifting by the
+// offset of their bounding boxes are moved, and the rest are removed from
+// old or added in new.
+func DiffScenes(old []Value, new []Value, tol float64) SceneDiff {
+	var d SceneDiff
+	oldLeft := append([]Value{}, old...)
+	var newLeft []Value
+	for _, v := range new {
+		if i := matchValue(oldLeft, func(o Value) bool { return approxEqual(o, v, tol) }); i >= 0 {
+			oldLeft = append(oldLeft[:i], oldLeft[i+1:]...)
+			d.Unchanged++
+		} else {
+			newLeft = append(newLeft, v)
+		}
+	}
+	for _, v := range newLeft {
+		minX, minY, _, _, bounded := valueBounds(v)
+		var dx, dy float64
+		i := -1
+		if bounded {
+			i = matchValue(oldLeft, func(o Value) bool {
+				oMinX, oMinY, _, _, ok := valueBounds(o)
+				dx, dy = minX-oMinX, minY-oMinY
+				return ok && kind(o) == kind(v) && approxEqual(o.shift(dx, dy), v, tol)
+			})
+		}
+		if i >= 0 {
+			d.Moved = append(d.Moved, Move{oldLeft[i], v, dx, dy})
+			oldLeft = append(oldLeft[:i], oldLeft[i+1:]...)
+		} else {
+			d.Added = append(d.Added, v)
+		}
+	}
+	d.Removed = oldLeft
+	return d
+}
+
+// matchValue returns the index of the first value satisfying match, or -1.
+func matchValue(vs []Value, match func(Value) bool) int {
+	for i, v := range vs {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Equal reports whether a and b are the same value. Angles are compared
+// modulo 2*pi, the corners of regions from any one in either direction, the
+// points of segments, paths and Beziers in either direction and the members
+// of PointSets and Collections in any order.
+func Equal(a Value, b Value) bool {
+	return approxEqual(a, b, 0)
+}
+
+// ApproxEqual is Equal with numbers allowed to differ by up to tol.
+func ApproxEqual(a Value, b Value, tol float64) bool {
+	return approxEqual(a, b, tol)
+}
+func approxEqual(a Value, b Value, tol float64) bool {
+	if pa, ok := regionPolygon(a); ok {
+		pb, ok := regionPolygon(b)
+		return ok && sameRing(pa.pts, pb.pts, tol)
+	}
+	switch ta := a.(type) {
+	case Point:
+		tb, ok := b.(Point)
+		return ok && closePoint(ta, tb, tol)
+	case Line:
+		tb, ok := b.(Line)
+		return ok && closeAngle(ta.Angle(), tb.Angle(), tol) && math.Abs(ta.d-tb.d) <= tol
+	case LineSegment:
+		tb, ok := b.(LineSegment)
+		return ok && sameCurve([]Point{{ta.x1, ta.y1}, {ta.x2, ta.y2}}, []Point{{tb.x1, tb.y1}, {tb.x2, tb.y2}}, tol)
+	case Ray:
+		tb, ok := b.(Ray)
+		return ok && closePoint(Point{ta.x, ta.y}, Point{tb.x, tb.y}, tol) && closeAngle(ta.angle, tb.angle, tol)
+	case Circle:
+		tb, ok := b.(Circle)
+		return ok && closePoint(Point{ta.x, ta.y}, Point{tb.x, tb.y}, tol) && math.Abs(ta.r-tb.r) <= tol
+	case Arc:
+		tb, ok := b.(Arc)
+		return ok && closePoint(Point{ta.x, ta.y}, Point{tb.x, tb.y}, tol) && math.Abs(ta.r-tb.r) <= tol &&
+			closeAngle(ta.start, tb.start, tol) && math.Abs(ta.sweep-tb.sweep) <= tol
+	case Bezier:
+		tb, ok := b.(Bezier)
+		return ok && sameCurve(ta.pts, tb.pts, tol)
+	case Path:
+		tb, ok := b.(Path)
+		return ok && sameCurve(ta.pts, tb.pts, tol)
+	case compoundCurve:
+		tb, ok := b.(compoundCurve)
+		if !ok || len(ta.parts) != len(tb.parts) {
+			return false
+		}
+		forward, backward := true, true
+		for i, n := 0, len(ta.parts); i < n; i++ {
+			forward = forward && approxEqual(ta.parts[i], tb.parts[i], tol)
+			backward = backward && approxEqual(ta.parts[i], tb.parts[n-1-i], tol)
+		}
+		return forward || backward
+	case pointSet:
+		tb, ok := b.(pointSet)
+		return ok && len(ta.pts) == len(tb.pts) && sameMembers(len(ta.pts), func(i int, j int) bool {
+			return closePoint(ta.pts[i], tb.pts[j], tol)
+		})
+	case collection:
+		tb, ok := b.(collection)
+		return ok && len(ta.vs) == len(tb.vs) && sameMembers(len(ta.vs), func(i int, j int) bool {
+			return approxEqual(ta.vs[i], tb.vs[j], tol)
+		})
+	}
+	// Nowhere and Everywhere
+	return a == b
+}
+func closePoint(p Point, q Point, tol float64) bool {
+	return math.Abs(p.x-q.x) <= tol && math.Abs(p.y-q.y) <= tol
+}
+
+// closeAngle compares a and b modulo 2*pi.
+func closeAngle(a float64, b float64, tol float64) bool {
+	d := math.Mod(math.Abs(a-b), 2*math.Pi)
+	return math.Min(d, 2*math.Pi-d) <= tol
+}
+
+// sameCurve compares the points of two curves in order or reversed.
+func sameCurve(a []Point, b []Point, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	n := len(a)
+	forward, backward := true, true
+	for i := range a {
+		forward = forward && closePoint(a[i], b[i], tol)
+		backward = backward && closePoint(a[i], b[n-1-i], tol)
+	}
+	return forward || backward
+}
+
+// sameRing compares the corners of two regions starting from any corner of
+// b and running in either direction.
+func sameRing(a []Point, b []Point, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	n := len(a)
+	for k := 0; k < n; k++ {
+		forward, backward := true, true
+		for i := 0; i < n && (forward || backward); i++ {
+			forward = forward && closePoint(a[i], b[(k+i)%n], tol)
+			backward = backward && closePoint(a[i], b[(k-i+n)%n], tol)
+		}
+		if forward || backward {
+			return true
+		}
+	}
+	return false
+}
+
+// sameMembers matches each of n members of one list to a different member
+// of another of the same length, ignoring their order.
+func sameMembers(n int, match func(int, int) bool) bool {
+	used := make([]bool, n)
+	for i := 0; i < n; i++ {
+		found := false
+		for j := 0; j < n; j++ {
+			if !used[j] && match(i, j) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApproxEqual(t *testing.T) {
+	square := NewPolygon(0, 0, 2, 0, 2, 2, 0, 2)
+	cases := []struct {
+		name string
+		a    Value
+		b    Value
+		want bool
+	}{
+		{"intersection of rect and triangle commutes",
+			Intersect(NewRect(0, 0, 2, 2), NewTriangle(-1, 1, 3, -1, 3, 3)),
+			Intersect(NewTriangle(-1, 1, 3, -1, 3, 3), NewRect(0, 0, 2, 2)), true},
+		{"polygon from another corner", square, NewPolygon(2, 2, 0, 2, 0, 0, 2, 0), true},
+		{"polygon the other way round", square, NewPolygon(0, 0, 0, 2, 2, 2, 2, 0), true},
+		{"rect as polygon", square, NewRect(0, 0, 2, 2), true},
+		{"polygon with a moved corner", square, NewPolygon(0, 0, 2, 0, 2, 2.1, 0, 2), false},
+		{"polygon with corners swapped", square, NewPolygon(0, 0, 2, 2, 2, 0, 0, 2), false},
+		{"reversed segment", NewLineSegment(0, 0, 1, 2), NewLineSegment(1, 2, 0, 0), true},
+		{"ray angle a turn apart", NewRay(1, 1, 0.5), NewRay(1, 1, 0.5+2*math.Pi), true},
+		{"point sets in any order", NewPointSet(0, 0, 1, 1, 2, 0), NewPointSet(2, 0, 0, 0, 1, 1), true},
+		{"point sets of different sizes", NewPointSet(0, 0, 1, 1), NewPointSet(0, 0, 1, 1, 2, 0), false},
+		{"collections in any order", NewCollection(Point{0, 0}, NewCircle(1, 1, 1)), NewCollection(NewCircle(1, 1, 1), Point{0, 0}), true},
+		{"circle and arc", NewCircle(0, 0, 1), NewArc(0, 0, 1, 0, 1), false},
+		{"point and nowhere", Point{0, 0}, Nowhere, false},
+		{"nowhere", Nowhere, Nowhere, true},
+	}
+	for _, c := range cases {
+		if got := ApproxEqual(c.a, c.b, 1e-9); got != c.want {
+			t.Errorf("%s: ApproxEqual(%#v, %#v) = %v", c.name, c.a, c.b, got)
+		}
+		if got := ApproxEqual(c.b, c.a, 1e-9); got != c.want {
+			t.Errorf("%s: ApproxEqual(%#v, %#v) = %v", c.name, c.b, c.a, got)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Difference returns the part of gv1 not covered by gv2, e.g. the one or
+// two pieces left of a segment when an overlapping segment is removed.
+// Results are closed sets: removing single points from a curve, or curves
+// from a region, leaves it unchanged. A region left with holes comes back as
+// a Collection of polygons without holes covering it. What is left of
+// Everywhere around a region cannot be held by a Value and is an error.
+func Difference(gv1 Value, gv2 Value) (Value, error) {
+	switch b := gv2.(type) {
+	case nowhere:
+		return gv1, nil
+	case everywhere:
+		return Nowhere, nil
+	case collection:
+		// removing the members one after the other removes their union
+		result := gv1
+		for _, m := range b.vs {
+			var err error
+			if result, err = Difference(result, m); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	if _, ok := gv1.(nowhere); ok || covers(gv2, gv1) {
+		return Nowhere, nil
+	}
+	switch a := gv1.(type) {
+	case everywhere:
+		if _, ok := regionPolygon(gv2); ok {
+			return nil, fmt.Errorf("Difference of Everywhere and %s is not a Value", kind(gv2))
+		}
+		return a, nil
+	case Point:
+		return a, nil
+	case pointSet:
+		var pts []Point
+		for _, p := range a.pts {
+			if isNowhere(p.intersect(gv2)) {
+				pts = append(pts, p)
+			}
+		}
+		return newPointSet(pts), nil
+	case collection:
+		return eachDifference(a.vs, gv2)
+	case compoundCurve:
+		parts := make([]Value, len(a.parts))
+		for i, c := range a.parts {
+			parts[i] = c
+		}
+		return eachDifference(parts, gv2)
+	case Path:
+		var parts []Value
+		for i := 1; i < len(a.pts); i++ {
+			p, q := a.pts[i-1], a.pts[i]
+			parts = append(parts, NewLineSegment(p.x, p.y, q.x, q.y))
+		}
+		return eachDifference(parts, gv2)
+	case Line:
+		sin, cos := a.sin, a.cos
+		return straightDifference(Point{a.d * sin, a.d * cos}, Point{cos, -sin}, math.Inf(-1), math.Inf(1), a.intersect(gv2)), nil
+	case Ray:
+		sin, cos := math.Sincos(a.angle)
+		return straightDifference(Point{a.x, a.y}, Point{cos, sin}, 0, math.Inf(1), a.intersect(gv2)), nil
+	case LineSegment:
+		return straightDifference(Point{a.x1, a.y1}, Point{a.x2 - a.x1, a.y2 - a.y1}, 0, 1, a.intersect(gv2)), nil
+	case Circle:
+		return arcDifference(Arc{a.x, a.y, a.r, 0, 2 * math.Pi}, a.intersect(gv2)), nil
+	case Arc:
+		return arcDifference(a, a.intersect(gv2)), nil
+	case Bezier:
+		if pb, ok := regionPolygon(gv2); ok {
+			return pb.cutCurve(a, func(p Point) bool { return !pb.inside(p) && !pb.onBoundary(p) }), nil
+		}
+	}
+	if pa, ok := regionPolygon(gv1); ok {
+		pb, ok := regionPolygon(gv2)
+		if !ok {
+			return gv1, nil
+		}
+		var parts []Value
+		for _, pw := range PolygonDifference(pa, pb) {
+			for _, pg := range withoutHoles(pw) {
+				parts = append(parts, pg)
+			}
+		}
+		return newCollection(parts), nil
+	}
+	if pointsOnly(gv1.intersect(gv2)) {
+		return gv1, nil
+	}
+	return nil, fmt.Errorf("Difference of %s and %s is not supported", kind(gv1), kind(gv2))
+}
+
+// eachDifference removes gv from every one of vs.
+func eachDifference(vs []Value, gv Value) (Value, error) {
+	parts := make([]Value, len(vs))
+	for i, v := range vs {
+		var err error
+		if parts[i], err = Difference(v, gv); err != nil {
+			return nil, err
+		}
+	}
+	return newCollection(parts), nil
+}
+
+// withoutHoles cuts pw into polygons without holes: the pieces holding a
+// hole are halved by a vertical line through it, which opens the hole to
+// the outside of both halves.
+func withoutHoles(pw PolygonWithHoles) []Polygon {
+	pieces := []Polygon{pw.Outer}
+	for _, h := range pw.Holes {
+		h = h.counterclockwise()
+		// between the two middle x coordinates of the hole, so the cut misses
+		// its vertices
+		var xs []float64
+		for _, p := range h.pts {
+			xs = append(xs, p.x)
+		}
+		sort.Float64s(xs)
+		n := 1
+		for _, x := range xs[1:] {
+			if x > xs[n-1] {
+				xs[n] = x
+				n++
+			}
+		}
+		cut := (xs[n/2-1] + xs[n/2]) / 2
+		var next []Polygon
+		for _, pg := range pieces {
+			minX, minY, maxX, maxY := pg.boundary().Bounds()
+			halves := []Polygon{pg}
+			if minX < cut && cut < maxX {
+				halves = nil
+				for _, r := range []Rect{{minX - 1, minY - 1, cut, maxY + 1}, {cut, minY - 1, maxX + 1, maxY + 1}} {
+					for _, pw := range PolygonIntersection(pg, r.toPolygon()) {
+						halves = append(halves, pw.Outer)
+					}
+				}
+			}
+			for _, half := range halves {
+				for _, pw := range PolygonDifference(half, h) {
+					next = append(next, pw.Outer)
+				}
+			}
+		}
+		pieces = next
+	}
+	return pieces
+}
+
+// straightDifference removes the pieces of cut from the points o + t*dir
+// with t in [tmin, tmax].
+func straightDifference(o Point, dir Point, tmin float64, tmax float64, cut Value) Value {
+	at := func(t float64) Point { return Point{o.x + t*dir.x, o.y + t*dir.y} }
+	param := func(p Point) float64 { return ((p.x-o.x)*dir.x + (p.y-o.y)*dir.y) / (dir.x*dir.x + dir.y*dir.y) }
+	var cuts [][2]float64
+	for _, m := range members(cut) {
+		switch mv := m.(type) {
+		case LineSegment:
+			t1, t2 := param(Point{mv.x1, mv.y1}), param(Point{mv.x2, mv.y2})
+			cuts = append(cuts, [2]float64{math.Min(t1, t2), math.Max(t1, t2)})
+		case Ray:
+			t := param(Point{mv.x, mv.y})
+			sin, cos := math.Sincos(mv.angle)
+			if cos*dir.x+sin*dir.y > 0 {
+				cuts = append(cuts, [2]float64{t, math.Inf(1)})
+			} else {
+				cuts = append(cuts, [2]float64{math.Inf(-1), t})
+			}
+		case Line:
+			cuts = append(cuts, [2]float64{math.Inf(-1), math.Inf(1)})
+		}
+	}
+	var parts []Value
+	scale := math.Hypot(dir.x, dir.y)
+	for _, piece := range complement(cuts, tmin, tmax, epsilon/scale) {
+		lo, hi := piece[0], piece[1]
+		switch {
+		case math.IsInf(lo, -1) && math.IsInf(hi, 1):
+			parts = append(parts, NewLine(math.Atan2(-dir.y, dir.x), (o.y*dir.x-o.x*dir.y)/scale))
+		case math.IsInf(lo, -1):
+			p := at(hi)
+			parts = append(parts, NewRay(p.x, p.y, math.Atan2(-dir.y, -dir.x)))
+		case math.IsInf(hi, 1):
+			p := at(lo)
+			parts = append(parts, NewRay(p.x, p.y, math.Atan2(dir.y, dir.x)))
+		default:
+			p, q := at(lo), at(hi)
+			parts = append(parts, NewLineSegment(p.x, p.y, q.x, q.y))
+		}
+	}
+	return newCollection(parts)
+}
+
+// arcDifference removes the pieces of cut from a.
+func arcDifference(a Arc, cut Value) Value {
+	var cuts [][2]float64
+	add := func(lo float64, sweep float64) {
+		cuts = append(cuts, [2]float64{lo, lo + sweep})
+		// a piece running past a full turn continues at the start
+		if lo+sweep > 2*math.Pi {
+			cuts = append(cuts, [2]float64{0, lo + sweep - 2*math.Pi})
+		}
+	}
+	for _, m := range members(cut) {
+		switch mv := m.(type) {
+		case Arc:
+			lo := normalizeAngle(mv.start - a.start)
+			if (2*math.Pi-lo)*a.r < epsilon {
+				lo = 0
+			}
+			add(lo, mv.sweep)
+		case Circle:
+			add(0, 2*math.Pi)
+		}
+	}
+	pieces := complement(cuts, 0, a.sweep, epsilon/a.r)
+	// on a full circle the pieces at both ends join across the start
+	if n := len(pieces); n > 1 && a.sweep == 2*math.Pi && pieces[0][0] == 0 && pieces[n-1][1] == 2*math.Pi {
+		pieces[0][0] = pieces[n-1][0] - 2*math.Pi
+		pieces = pieces[:n-1]
+	}
+	parts := make([]Value, len(pieces))
+	for i, piece := range pieces {
+		parts[i] = NewArc(a.x, a.y, a.r, a.start+piece[0], a.start+piece[1])
+	}
+	return newCollection(parts)
+}
+
+// complement returns the parts of [tmin, tmax] longer than tol not covered
+// by the intervals cuts.
+func complement(cuts [][2]float64, tmin float64, tmax float64, tol float64) [][2]float64 {
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i][0] < cuts[j][0] })
+	var result [][2]float64
+	from := tmin
+	for _, c := range cuts {
+		if c[0]-from > tol {
+			result = append(result, [2]float64{from, math.Min(c[0], tmax)})
+		}
+		from = math.Max(from, c[1])
+		if from >= tmax {
+			return result
+		}
+	}
+	if tmax-from > tol {
+		result = append(result, [2]float64{from, tmax})
+	}
+	return result
+}
+
+// members lists the components of gv.
+func members(gv Value) []Value {
+	switch v := gv.(type) {
+	case nowhere:
+		return nil
+	case collection:
+		return v.vs
+	case pointSet:
+		ms := make([]Value, len(v.pts))
+		for i, p := range v.pts {
+			ms[i] = p
+		}
+		return ms
+	}
+	return []Value{gv}
+}
+
+// pointsOnly reports whether gv is made of isolated points or nothing.
+func pointsOnly(gv Value) bool {
+	switch gv.(type) {
+	case nowhere, Point, pointSet:
+		return true
+	}
+	return false
+}
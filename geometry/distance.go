@@ -0,0 +1,180 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// Distance returns the smallest distance between a point of a and a point
+// of b, 0 if they intersect. It is undefined, reported by false, if either
+// is Nowhere or Everywhere. Curves and circles are measured along flattened
+// copies.
+func Distance(a Value, b Value) (float64, bool) {
+	for _, v := range []Value{a, b} {
+		switch v.(type) {
+		case nowhere, everywhere:
+			return 0, false
+		}
+	}
+	if !isNowhere(a.intersect(b)) {
+		return 0, true
+	}
+	d := math.Inf(1)
+	for _, x := range distancePieces(a) {
+		for _, y := range distancePieces(b) {
+			d = math.Min(d, pieceDistance(x, y))
+		}
+	}
+	return d, true
+}
+
+// distancePieces breaks gv into points, segments, rays and lines.
+func distancePieces(gv Value) []Value {
+	switch v := gv.(type) {
+	case Line, Ray:
+		return []Value{v}
+	case collection:
+		var result []Value
+		for _, m := range v.vs {
+			result = append(result, distancePieces(m)...)
+		}
+		return result
+	}
+	var result []Value
+	for _, pl := range Polylines(gv) {
+		if len(pl) == 1 {
+			result = append(result, pl[0])
+		}
+		for i := 1; i < len(pl); i++ {
+			if !realClosePoint(pl[i-1], pl[i]) {
+				result = append(result, LineSegment{pl[i-1].x, pl[i-1].y, pl[i].x, pl[i].y})
+			}
+		}
+	}
+	return result
+}
+
+// distanceAnchors returns points of the pieces where the distance to a
+// piece they do not meet can be smallest.
+func distanceAnchors(pieces []Value) []Point {
+	var result []Point
+	for _, piece := range pieces {
+		switch v := piece.(type) {
+		case Point:
+			result = append(result, v)
+		case LineSegment:
+			result = append(result, Point{v.x1, v.y1}, Point{v.x2, v.y2})
+		case Ray:
+			result = append(result, Point{v.x, v.y})
+		case Line:
+			result = append(result, Point{v.d * v.sin, v.d * v.cos})
+		}
+	}
+	return result
+}
+func pieceDistance(x Value, y Value) float64 {
+	if !isNowhere(x.intersect(y)) {
+		return 0
+	}
+	p, q := pieceClosest(x, y)
+	return math.Hypot(p.x-q.x, p.y-q.y)
+}
+
+// pieceClosest returns the closest points of two pieces that do not meet.
+func pieceClosest(x Value, y Value) (Point, Point) {
+	var bestX, bestY Point
+	d := math.Inf(1)
+	for _, p := range distanceAnchors([]Value{x}) {
+		if q := closestOn(p, y); math.Hypot(p.x-q.x, p.y-q.y) < d {
+			bestX, bestY, d = p, q, math.Hypot(p.x-q.x, p.y-q.y)
+		}
+	}
+	for _, p := range distanceAnchors([]Value{y}) {
+		if q := closestOn(p, x); math.Hypot(p.x-q.x, p.y-q.y) < d {
+			bestX, bestY, d = q, p, math.Hypot(p.x-q.x, p.y-q.y)
+		}
+	}
+	return bestX, bestY
+}
+
+// ClosestPoints returns a point of a and a point of b at the smallest
+// distance between them, twice the same point of both if they intersect.
+// As for Distance, it is undefined, reported by false, if either is Nowhere
+// or Everywhere, and curves and circles are measured along flattened copies.
+func ClosestPoints(a Value, b Value) (Point, Point, bool) {
+	d, ok := Distance(a, b)
+	if !ok {
+		return Point{}, Point{}, false
+	}
+	if d == 0 {
+		if shared := distanceAnchors(distancePieces(a.intersect(b))); len(shared) > 0 {
+			return shared[0], shared[0], true
+		}
+	}
+	var bestA, bestB Point
+	d = math.Inf(1)
+	for _, x := range distancePieces(a) {
+		for _, y := range distancePieces(b) {
+			p, q := pieceClosest(x, y)
+			if dist := math.Hypot(p.x-q.x, p.y-q.y); dist < d {
+				bestA, bestB, d = p, q, dist
+			}
+		}
+	}
+	return bestA, bestB, true
+}
+func pointDistance(p Point, piece Value) float64 {
+	q := closestOn(p, piece)
+	return math.Hypot(p.x-q.x, p.y-q.y)
+}
+
+// Project returns the point of a line, ray or segment closest to p, the
+// foot of the perpendicular unless that falls beyond an end point.
+func Project(p Point, onto Value) Value {
+	switch onto.(type) {
+	case Point, Line, Ray, LineSegment:
+		return closestOn(p, onto)
+	}
+	panic("Project expects a Point, Line, Ray or LineSegment")
+}
+
+// closestOn returns the point of a piece closest to p.
+func closestOn(p Point, piece Value) Point {
+	switch v := piece.(type) {
+	case Point:
+		return v
+	case LineSegment:
+		return lerp(Point{v.x1, v.y1}, Point{v.x2, v.y2}, v.param(p))
+	case Ray:
+		t := math.Max(0, v.param(p))
+		sin, cos := math.Sincos(v.angle)
+		return Point{v.x + t*cos, v.y + t*sin}
+	case Line:
+		e := v.sin*p.x + v.cos*p.y - v.d
+		return Point{p.x - e*v.sin, p.y - e*v.cos}
+	}
+	panic("Should never been reached")
+}
@@ -0,0 +1,49 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// Dual maps between points and lines with the standard convention
+//
+//	point (a, b)  <->  line y = a*x - b
+//
+// so a point lies above a line exactly when the dual of the line lies
+// above the dual of the point, and Dual(Dual(v)) == v. Vertical lines have no dual point.
+// Nowhere is its own dual.
+func Dual(gv Value) Value {
+	switch v := gv.(type) {
+	case nowhere:
+		return Nowhere
+	case Point:
+		// a*x - y = b, normalised so that (sin, cos) is a unit vector
+		return lineFromNormal(v.x, -1, v.y)
+	case Line:
+		if realClose(v.cos, 0) {
+			panic("Vertical lines have no dual")
+		}
+		// y = -tan(angle)*x + d/cos(angle)
+		return Point{-v.sin / v.cos, -v.d / v.cos}
+	}
+	panic("Dual is only defined for points and lines")
+}
@@ -0,0 +1,307 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+// Package exact is a version of the geometry kernel for points, lines and
+// line segments with rational coordinates. Intersections are computed
+// without rounding, so chains of them do not drift; convert to the float
+// kernel with Float once the exact work is done.
+package exact
+
+import (
+	"fmt"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"math"
+	"math/big"
+)
+
+type Value interface {
+	shift(dx *big.Rat, dy *big.Rat) Value
+	intersect(other Value) Value
+	// Float returns the nearest value of the float kernel.
+	Float() geometry.Value
+	fmt.GoStringer
+}
+
+type nowhere struct {
+}
+
+// Point is the point (x, y).
+type Point struct {
+	x *big.Rat
+	y *big.Rat
+}
+
+// Line is the line a*x + b*y = c.
+type Line struct {
+	a *big.Rat
+	b *big.Rat
+	c *big.Rat
+}
+
+// LineSegment runs from p to q, with p before q in x, then y.
+type LineSegment struct {
+	p Point
+	q Point
+}
+
+/* nowhere */
+var Nowhere = nowhere{}
+
+func (nw nowhere) shift(dx *big.Rat, dy *big.Rat) Value {
+	return Nowhere
+}
+func (nw nowhere) intersect(other Value) Value {
+	return Nowhere
+}
+func (nw nowhere) Float() geometry.Value {
+	return geometry.Nowhere
+}
+func (nw nowhere) GoString() string {
+	return "\"Nowhere\""
+}
+
+/* point */
+func NewPoint(x *big.Rat, y *big.Rat) Point {
+	return Point{new(big.Rat).Set(x), new(big.Rat).Set(y)}
+}
+func (p Point) shift(dx *big.Rat, dy *big.Rat) Value {
+	return Point{add(p.x, dx), add(p.y, dy)}
+}
+func (p Point) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case Point:
+		if p.equal(ot) {
+			return p
+		}
+		return Nowhere
+	case Line:
+		if ot.contains(p) {
+			return p
+		}
+		return Nowhere
+	case LineSegment:
+		if ot.contains(p) {
+			return p
+		}
+		return Nowhere
+	}
+	panic("Should never been reached")
+}
+func (p Point) Float() geometry.Value {
+	x, _ := p.x.Float64()
+	y, _ := p.y.Float64()
+	return geometry.NewPoint(x, y)
+}
+func (p Point) GoString() string {
+	return fmt.Sprintf("{\"Point\":[%q,%q]}", p.x.RatString(), p.y.RatString())
+}
+func (p Point) X() *big.Rat {
+	return new(big.Rat).Set(p.x)
+}
+func (p Point) Y() *big.Rat {
+	return new(big.Rat).Set(p.y)
+}
+func (p Point) equal(q Point) bool {
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
+}
+
+// less orders points by x, then y.
+func (p Point) less(q Point) bool {
+	if c := p.x.Cmp(q.x); c != 0 {
+		return c < 0
+	}
+	return p.y.Cmp(q.y) < 0
+}
+
+/* line */
+func NewLine(a *big.Rat, b *big.Rat, c *big.Rat) Line {
+	if a.Sign() == 0 && b.Sign() == 0 {
+		panic("A Line needs a or b to be non-zero")
+	}
+	return Line{new(big.Rat).Set(a), new(big.Rat).Set(b), new(big.Rat).Set(c)}
+}
+
+// NewLineThrough returns the line through two distinct points.
+func NewLineThrough(p Point, q Point) Line {
+	if p.equal(q) {
+		panic("A Line needs two distinct points")
+	}
+	a, b := sub(q.y, p.y), sub(p.x, q.x)
+	return Line{a, b, add(mul(a, p.x), mul(b, p.y))}
+}
+func (ln Line) shift(dx *big.Rat, dy *big.Rat) Value {
+	return Line{ln.a, ln.b, add(ln.c, add(mul(ln.a, dx), mul(ln.b, dy)))}
+}
+func (ln Line) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere, Point:
+		return ot.intersect(ln)
+	case Line:
+		det := sub(mul(ln.a, ot.b), mul(ot.a, ln.b))
+		if det.Sign() == 0 {
+			// parallel, the same line if c is in proportion too
+			if sub(mul(ln.a, ot.c), mul(ot.a, ln.c)).Sign() == 0 && sub(mul(ln.b, ot.c), mul(ot.b, ln.c)).Sign() == 0 {
+				return ln
+			}
+			return Nowhere
+		}
+		x := new(big.Rat).Quo(sub(mul(ln.c, ot.b), mul(ot.c, ln.b)), det)
+		y := new(big.Rat).Quo(sub(mul(ln.a, ot.c), mul(ot.a, ln.c)), det)
+		return Point{x, y}
+	case LineSegment:
+		switch v := ln.intersect(ot.line()).(type) {
+		case Line:
+			return ot
+		case Point:
+			return v.intersect(ot)
+		}
+		return Nowhere
+	}
+	panic("Should never been reached")
+}
+
+// Float returns the float line sin(angle)*x + cos(angle)*y = d.
+func (ln Line) Float() geometry.Value {
+	a, _ := ln.a.Float64()
+	b, _ := ln.b.Float64()
+	c, _ := ln.c.Float64()
+	h := math.Hypot(a, b)
+	return geometry.NewLine(math.Atan2(a, b), c/h)
+}
+func (ln Line) GoString() string {
+	return fmt.Sprintf("{\"Line\":[%q,%q,%q]}", ln.a.RatString(), ln.b.RatString(), ln.c.RatString())
+}
+func (ln Line) contains(p Point) bool {
+	return add(mul(ln.a, p.x), mul(ln.b, p.y)).Cmp(ln.c) == 0
+}
+
+/* line segment */
+
+// NewLineSegment returns the segment from p to q, or p if they are equal.
+func NewLineSegment(p Point, q Point) Value {
+	switch {
+	case p.equal(q):
+		return p
+	case q.less(p):
+		return LineSegment{q, p}
+	}
+	return LineSegment{p, q}
+}
+func (ls LineSegment) shift(dx *big.Rat, dy *big.Rat) Value {
+	return LineSegment{ls.p.shift(dx, dy).(Point), ls.q.shift(dx, dy).(Point)}
+}
+func (ls LineSegment) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere, Point, Line:
+		return ot.intersect(ls)
+	case LineSegment:
+		switch v := ls.line().intersect(ot.line()).(type) {
+		case Point:
+			if ls.contains(v) && ot.contains(v) {
+				return v
+			}
+			return Nowhere
+		case Line:
+			// collinear, the overlap runs from the later start to the
+			// earlier end
+			start, end := ls.p, ls.q
+			if start.less(ot.p) {
+				start = ot.p
+			}
+			if ot.q.less(end) {
+				end = ot.q
+			}
+			if end.less(start) {
+				return Nowhere
+			}
+			return NewLineSegment(start, end)
+		}
+		return Nowhere
+	}
+	panic("Should never been reached")
+}
+func (ls LineSegment) Float() geometry.Value {
+	x1, _ := ls.p.x.Float64()
+	y1, _ := ls.p.y.Float64()
+	x2, _ := ls.q.x.Float64()
+	y2, _ := ls.q.y.Float64()
+	return geometry.NewLineSegment(x1, y1, x2, y2)
+}
+func (ls LineSegment) GoString() string {
+	return fmt.Sprintf("{\"LineSegment\":[%q,%q,%q,%q]}", ls.p.x.RatString(), ls.p.y.RatString(), ls.q.x.RatString(), ls.q.y.RatString())
+}
+func (ls LineSegment) Endpoints() (Point, Point) {
+	return ls.p, ls.q
+}
+func (ls LineSegment) line() Line {
+	return NewLineThrough(ls.p, ls.q)
+}
+
+// contains reports whether p lies on ls, end points included.
+func (ls LineSegment) contains(p Point) bool {
+	return ls.line().contains(p) && !p.less(ls.p) && !ls.q.less(p)
+}
+
+func Shift(dx *big.Rat, dy *big.Rat, v Value) Value {
+	return v.shift(dx, dy)
+}
+func Intersect(v1 Value, v2 Value) Value {
+	return v1.intersect(v2)
+}
+
+// FromFloat converts a float Point, Line or LineSegment exactly.
+func FromFloat(gv geometry.Value) Value {
+	switch v := gv.(type) {
+	case geometry.Point:
+		return Point{rat(v.X()), rat(v.Y())}
+	case geometry.Line:
+		sin, cos := math.Sincos(v.Angle())
+		return Line{rat(sin), rat(cos), rat(v.D())}
+	case geometry.LineSegment:
+		p, q := v.Endpoints()
+		return NewLineSegment(FromFloat(p).(Point), FromFloat(q).(Point))
+	}
+	if gv == geometry.Nowhere {
+		return Nowhere
+	}
+	panic(fmt.Sprintf("Cannot convert %s to an exact value", gv))
+}
+
+func rat(f float64) *big.Rat {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		panic("Cannot convert infinite or NaN coordinates")
+	}
+	return new(big.Rat).SetFloat64(f)
+}
+func add(a *big.Rat, b *big.Rat) *big.Rat {
+	return new(big.Rat).Add(a, b)
+}
+func sub(a *big.Rat, b *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(a, b)
+}
+func mul(a *big.Rat, b *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(a, b)
+}
@@ -0,0 +1,56 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+)
+
+// Frame is a local coordinate system. Its origin lies at (x, y) in world
+// coordinates and its axes are the world axes turned counterclockwise by
+// angle.
+type Frame struct {
+	x     float64
+	y     float64
+	angle float64
+}
+
+/* Frame */
+func NewFrame(x float64, y float64, angle float64) Frame {
+	return Frame{x, y, angle}
+}
+func (f Frame) GoString() string {
+	return fmt.Sprintf("{\"Frame\":[%v,%v,%v]}", f.x, f.y, f.angle)
+}
+
+// ToWorld converts gv from coordinates local to f into world coordinates.
+func (f Frame) ToWorld(gv Value) Value {
+	return gv.rotate(f.angle).shift(f.x, f.y)
+}
+
+// ToLocal converts gv from world coordinates into coordinates local to f.
+func (f Frame) ToLocal(gv Value) Value {
+	return gv.shift(-f.x, -f.y).rotate(-f.angle)
+}
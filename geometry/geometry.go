@@ -27,29 +27,52 @@ package geometry
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
 const epsilon = 0.00001
 
 type Value interface {
 	shift(dx float64, dy float64) Value
+	mirror(fx float64, fy float64) Value
+	scale(sx float64, sy float64) Value
+	rotate(theta float64) Value
 	intersect(other Value) Value
 	fmt.GoStringer
+	fmt.Stringer
+}
+
+// readable formats f for String, rounded to three decimals.
+func readable(f float64) string {
+	r := math.Round(f*1000) / 1000
+	if r == 0 {
+		r = 0 // no "-0"
+	}
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+func readablePoints(pts []Point) string {
+	s := make([]string, len(pts))
+	for i, p := range pts {
+		s[i] = fmt.Sprintf("(%s, %s)", readable(p.x), readable(p.y))
+	}
+	return "(" + strings.Join(s, ", ") + ")"
 }
 
 type nowhere struct {
 }
 type everywhere struct {
 }
-type point struct {
+type Point struct {
 	x float64
 	y float64
 }
-type line struct {
-	angle float64
-	d     float64
+type Line struct {
+	sin float64
+	cos float64
+	d   float64
 }
-type lineSegment struct {
+type LineSegment struct {
 	x1 float64
 	y1 float64
 	x2 float64
@@ -62,12 +85,24 @@ var Nowhere = nowhere{}
 func (nw nowhere) shift(dx float64, dy float64) Value {
 	return Nowhere
 }
+func (nw nowhere) mirror(fx float64, fy float64) Value {
+	return Nowhere
+}
+func (nw nowhere) scale(sx float64, sy float64) Value {
+	return Nowhere
+}
+func (nw nowhere) rotate(theta float64) Value {
+	return Nowhere
+}
 func (nw nowhere) intersect(other Value) Value {
 	return Nowhere
 }
 func (nw nowhere) GoString() string {
 	return "\"Nowhere\""
 }
+func (nw nowhere) String() string {
+	return "Nowhere"
+}
 
 /* nowhere */
 var Everywhere = everywhere{}
@@ -75,166 +110,264 @@ var Everywhere = everywhere{}
 func (ew everywhere) shift(dx float64, dy float64) Value {
 	return Everywhere
 }
+func (ew everywhere) mirror(fx float64, fy float64) Value {
+	return Everywhere
+}
+func (ew everywhere) scale(sx float64, sy float64) Value {
+	return Everywhere
+}
+func (ew everywhere) rotate(theta float64) Value {
+	return Everywhere
+}
 func (ew everywhere) intersect(other Value) Value {
 	return other
 }
 func (ew everywhere) GoString() string {
 	return "\"Everywhere\""
 }
+func (ew everywhere) String() string {
+	return "Everywhere"
+}
 
 /* point */
-func NewPoint(x float64, y float64) point {
-	return point{x, y}
+func NewPoint(x float64, y float64) Point {
+	return Point{x, y}
+}
+func (p Point) shift(dx float64, dy float64) Value {
+	return Point{x: p.x + dx, y: p.y + dy}
 }
-func (p point) shift(dx float64, dy float64) Value {
-	return point{x: p.x + dx, y: p.y + dy}
+func (p Point) mirror(fx float64, fy float64) Value {
+	return Point{fx * p.x, fy * p.y}
 }
-func (p point) intersect(other Value) Value {
+func (p Point) scale(sx float64, sy float64) Value {
+	return Point{sx * p.x, sy * p.y}
+}
+func (p Point) rotate(theta float64) Value {
+	sin, cos := math.Sincos(theta)
+	return Point{p.x*cos - p.y*sin, p.x*sin + p.y*cos}
+}
+func (p Point) intersect(other Value) Value {
 	switch ot := other.(type) {
 	case nowhere:
 		return Nowhere
 	case everywhere:
 		return p
-	case point:
+	case Point:
 		if realClose(p.x, ot.x) && realClose(p.y, ot.y) {
 			return p
 		} else {
 			return Nowhere
 		}
-	case line, lineSegment:
+	case Line, LineSegment, Bezier, pointSet, compoundCurve, Path, Circle, Arc, Ray, Polygon, Rect, Triangle, collection:
 		return ot.intersect(p)
 	}
 	panic("Should never been reached")
 }
-func (p point) GoString() string {
+func (p Point) GoString() string {
 	return fmt.Sprintf("{\"Point\":[%v,%v]}", p.x, p.y)
 }
+func (p Point) String() string {
+	return fmt.Sprintf("Point(%s, %s)", readable(p.x), readable(p.y))
+}
+func (p Point) X() float64 {
+	return p.x
+}
+func (p Point) Y() float64 {
+	return p.y
+}
 
-/* line: sin(angle)*x + cos(angle)*y = d */
-func NewLine(angle float64, d float64) line {
-	// make d positiv and angle between 0 and 2pi
+/* line: sin(angle)*x + cos(angle)*y = d, kept as the normal (sin, cos) */
+func NewLine(angle float64, d float64) Line {
+	sin, cos := math.Sincos(angle)
+	// make d positive
 	if d < 0 {
-		angle = angle + math.Pi
-		d = -d
+		return Line{-sin, -cos, -d}
 	}
-	angle = math.Mod(angle, 2*math.Pi)
-	if angle < 0 {
-		angle = angle + 2*math.Pi
+	return Line{sin, cos, d}
+}
+
+// lineFromNormal returns the line nx*x + ny*y = d, with the normal scaled
+// to unit length and flipped to make d positive.
+func lineFromNormal(nx float64, ny float64, d float64) Line {
+	h := math.Hypot(nx, ny)
+	if d < 0 {
+		h = -h
 	}
-	return line{angle, d}
+	return Line{nx / h, ny / h, d / h}
 }
-func (ln line) shift(dx float64, dy float64) Value {
-	return line{ln.angle, ln.d + math.Sin(ln.angle)*dx + math.Cos(ln.angle)*dy}
+func (ln Line) shift(dx float64, dy float64) Value {
+	return lineFromNormal(ln.sin, ln.cos, ln.d+ln.sin*dx+ln.cos*dy)
 }
-func (ln line) intersect(other Value) Value {
+func (ln Line) mirror(fx float64, fy float64) Value {
+	// x -> -x turns the normal (sin, cos) into (-sin, cos), y -> -y into
+	// (sin, -cos)
+	return Line{math.Copysign(1, fx) * ln.sin, math.Copysign(1, fy) * ln.cos, ln.d}
+}
+func (ln Line) scale(sx float64, sy float64) Value {
+	// sin*x + cos*y = d turns into sy*sin*x + sx*cos*y = sx*sy*d
+	return lineFromNormal(sy*ln.sin, sx*ln.cos, sx*sy*ln.d)
+}
+func (ln Line) rotate(theta float64) Value {
+	// the normal (sin(angle), cos(angle)) turns into (sin(angle-theta), cos(angle-theta))
+	sin, cos := math.Sincos(theta)
+	return Line{ln.sin*cos - ln.cos*sin, ln.cos*cos + ln.sin*sin, ln.d}
+}
+func (ln Line) intersect(other Value) Value {
 	switch ot := other.(type) {
 	case nowhere:
 		return Nowhere
 	case everywhere:
 		return ln
-	case point:
-		if realClose(math.Sin(ln.angle)*ot.x+math.Cos(ln.angle)*ot.y, ln.d) {
+	case Point:
+		if realClose(ln.sin*ot.x+ln.cos*ot.y, ln.d) {
 			return ot
 		} else {
 			return Nowhere
 		}
-	case line:
-		if realCloseAngle(ln.angle, ot.angle) {
-			if realClose(ln.d, ot.d) {
-				return ln
-			} else {
-				return Nowhere
-			}
-		} else if realCloseAngle(ln.angle, ot.angle+math.Pi) {
-			if realClose(ln.d, 0) && realClose(ot.d, 0) {
+	case Line:
+		if ln.parallel(ot) {
+			if realClose(ln.sin*ot.sin+ln.cos*ot.cos, 1) {
+				if realClose(ln.d, ot.d) {
+					return ln
+				}
+			} else if realClose(ln.d, -ot.d) {
 				return ln
-			} else {
-				return Nowhere
 			}
+			return Nowhere
 		} else {
-			x := (ln.d*math.Cos(ot.angle) - ot.d*math.Cos(ln.angle)) / math.Sin(ln.angle-ot.angle)
-			y := (ot.d*math.Sin(ln.angle) - ln.d*math.Sin(ot.angle)) / math.Sin(ln.angle-ot.angle)
-			return point{x, y}
+			det := ln.sin*ot.cos - ln.cos*ot.sin
+			x := (ln.d*ot.cos - ot.d*ln.cos) / det
+			y := (ot.d*ln.sin - ln.d*ot.sin) / det
+			return Point{x, y}
 		}
-	case lineSegment:
+	case LineSegment, Bezier, pointSet, compoundCurve, Path, Circle, Arc, Ray, Polygon, Rect, Triangle, collection:
 		return ot.intersect(ln)
 	}
 	panic("Should never been reached")
 }
-func (ln line) GoString() string {
-	return fmt.Sprintf("{\"Line\":[%v,%v]}", ln.angle, ln.d)
+func (ln Line) GoString() string {
+	return fmt.Sprintf("{\"Line\":[%v,%v]}", ln.Angle(), ln.d)
+}
+func (ln Line) String() string {
+	return fmt.Sprintf("Line(angle=%s, d=%s)", readable(ln.Angle()), readable(ln.d))
+}
+
+// Angle returns the angle of the normal, between 0 and 2pi.
+func (ln Line) Angle() float64 {
+	angle := math.Atan2(ln.sin, ln.cos)
+	if angle < 0 {
+		angle = angle + 2*math.Pi
+	} else if angle == 0 {
+		angle = 0 // no "-0"
+	}
+	return angle
+}
+func (ln Line) D() float64 {
+	return ln.d
+}
+
+// SlopeIntercept returns m and b with ln being y = m*x + b. ok is false for
+// lines vertical within the package tolerance, which have no slope.
+func (ln Line) SlopeIntercept() (m float64, b float64, ok bool) {
+	if math.Abs(ln.cos) < epsilon {
+		return 0, 0, false
+	}
+	return -ln.sin / ln.cos, ln.d / ln.cos, true
+}
+
+// PointAt returns the point at signed distance t from the point of ln
+// closest to the origin, counted in the direction (cos, -sin) along the
+// normal (sin, cos).
+func (ln Line) PointAt(t float64) Point {
+	return Point{ln.d*ln.sin + t*ln.cos, ln.d*ln.cos - t*ln.sin}
+}
+
+// parallel reports whether ln and ot have the same or opposite normals.
+func (ln Line) parallel(ot Line) bool {
+	return math.Abs(ln.sin*ot.cos-ln.cos*ot.sin) < epsilon
 }
 
 /* lineSegment */
 func NewLineSegment(x1 float64, y1 float64, x2 float64, y2 float64) Value {
 	if realClose(x1, x2) {
 		if realClose(y1, y2) {
-			return point{x1, y1}
+			return Point{x1, y1}
 		} else if y1 < y2 {
-			return lineSegment{x1, y1, x2, y2}
+			return LineSegment{x1, y1, x2, y2}
 		} else {
-			return lineSegment{x2, y2, x1, y1}
+			return LineSegment{x2, y2, x1, y1}
 		}
 	} else {
 		if x1 < x2 {
-			return lineSegment{x1, y1, x2, y2}
+			return LineSegment{x1, y1, x2, y2}
 		} else {
-			return lineSegment{x2, y2, x1, y1}
+			return LineSegment{x2, y2, x1, y1}
 		}
 	}
 }
-func (ls lineSegment) shift(dx float64, dy float64) Value {
-	return lineSegment{ls.x1 + dx, ls.y1 + dy, ls.x2 + dx, ls.y2 + dy}
+func (ls LineSegment) shift(dx float64, dy float64) Value {
+	return LineSegment{ls.x1 + dx, ls.y1 + dy, ls.x2 + dx, ls.y2 + dy}
 }
-func (ls lineSegment) intersect(other Value) Value {
+func (ls LineSegment) mirror(fx float64, fy float64) Value {
+	return NewLineSegment(fx*ls.x1, fy*ls.y1, fx*ls.x2, fy*ls.y2)
+}
+func (ls LineSegment) scale(sx float64, sy float64) Value {
+	return NewLineSegment(sx*ls.x1, sy*ls.y1, sx*ls.x2, sy*ls.y2)
+}
+func (ls LineSegment) rotate(theta float64) Value {
+	p1 := Point{ls.x1, ls.y1}.rotate(theta).(Point)
+	p2 := Point{ls.x2, ls.y2}.rotate(theta).(Point)
+	return NewLineSegment(p1.x, p1.y, p2.x, p2.y)
+}
+func (ls LineSegment) intersect(other Value) Value {
 	switch ot := other.(type) {
 	case nowhere:
 		return Nowhere
 	case everywhere:
 		return ls
-	case point:
+	case Point:
 		p := ls.toLine().intersect(ot)
 		switch pt := p.(type) {
 		case nowhere:
 			return Nowhere
-		case point:
+		case Point:
 			if between(ls.x1, pt.x, ls.x2) && between(ls.y1, pt.y, ls.y2) {
 				return pt
 			} else {
 				return Nowhere
 			}
 		}
-	case line:
+	case Line:
 		p := ls.toLine().intersect(ot)
 		switch pt := p.(type) {
 		case nowhere:
 			return Nowhere
-		case point:
+		case Point:
 			if between(ls.x1, pt.x, ls.x2) && between(ls.y1, pt.y, ls.y2) {
 				return pt
 			} else {
 				return Nowhere
 			}
-		case line:
+		case Line:
 			return ls
 		}
-	case lineSegment:
+	case LineSegment:
 		p := ls.toLine().intersect(ot)
 		switch pt := p.(type) {
 		case nowhere:
 			return Nowhere
-		case point:
-			if between(ls.x1, pt.x, ls.x2) && between(ls.y1, pt.y, ls.y2) {
+		case Point:
+			if segmentsCross(ls, ot) || between(ls.x1, pt.x, ls.x2) && between(ls.y1, pt.y, ls.y2) {
 				return pt
 			} else {
 				return Nowhere
 			}
-		case lineSegment:
+		case LineSegment:
 			// ls and ot ar on the same line
 			if realClose(ls.x1, ot.x2) && realClose(ls.y1, ot.y2) {
-				return point{ls.x1, ls.y1} // touch in one point
+				return Point{ls.x1, ls.y1} // touch in one point
 			} else if realClose(ls.x2, ot.x1) && realClose(ls.y2, ot.y1) {
-				return point{ls.x2, ls.y2} // touch in one point
+				return Point{ls.x2, ls.y2} // touch in one point
 			} else if between(ls.x1, ot.x1, ls.x2) && between(ls.y1, ot.y1, ls.y2) {
 				x1 := ot.x1
 				y1 := ot.y1
@@ -247,7 +380,7 @@ func (ls lineSegment) intersect(other Value) Value {
 					x2 = ls.x2
 					y2 = ls.y2
 				}
-				return lineSegment{x1, y1, x2, y2}
+				return LineSegment{x1, y1, x2, y2}
 			} else if between(ot.x1, ls.x1, ot.x2) && between(ot.y1, ls.y1, ot.y2) {
 				x1 := ls.x1
 				y1 := ls.y1
@@ -260,32 +393,46 @@ func (ls lineSegment) intersect(other Value) Value {
 					x2 = ot.x2
 					y2 = ot.y2
 				}
-				return lineSegment{x1, y1, x2, y2}
+				return LineSegment{x1, y1, x2, y2}
 			} else {
 				return Nowhere
 			}
 		}
+	case Bezier, pointSet, compoundCurve, Path, Circle, Arc, Ray, Polygon, Rect, Triangle, collection:
+		return ot.intersect(ls)
 	}
 	panic("Should never been reached")
 }
-func (ls lineSegment) GoString() string {
+func (ls LineSegment) GoString() string {
 	return fmt.Sprintf("{\"LineSegment\":[%v,%v,%v,%v]}", ls.x1, ls.y1, ls.x2, ls.y2)
 }
-func (ls lineSegment) toLine() line {
-	var angle float64
-	dx := ls.x1 - ls.x2
-	if dx == 0 {
-		angle = math.Pi / 2
-	} else {
-		dy := ls.y2 - ls.y1
-		angle = math.Atan(dy / dx)
-	}
-	return line{angle, ls.x1*math.Sin(angle) + ls.y1*math.Cos(angle)}
+func (ls LineSegment) String() string {
+	return fmt.Sprintf("LineSegment(%s, %s -> %s, %s)", readable(ls.x1), readable(ls.y1), readable(ls.x2), readable(ls.y2))
+}
+func (ls LineSegment) Endpoints() (Point, Point) {
+	return Point{ls.x1, ls.y1}, Point{ls.x2, ls.y2}
+}
+func (ls LineSegment) Midpoint() Point {
+	return ls.At(0.5)
+}
+
+// PointAt returns the point a fraction t of the way from the first end
+// point to the second. t is clamped to [0, 1], so the end points are
+// returned for t beyond them.
+func (ls LineSegment) PointAt(t float64) Point {
+	return ls.At(math.Max(0, math.Min(1, t)))
+}
+func (ls LineSegment) toLine() Line {
+	nx, ny := ls.y2-ls.y1, ls.x1-ls.x2
+	return lineFromNormal(nx, ny, ls.x1*nx+ls.y1*ny)
 }
 
 func realClose(f1 float64, f2 float64) bool {
 	return math.Abs(f1-f2) < epsilon
 }
+func realClosePoint(p1 Point, p2 Point) bool {
+	return realClose(p1.x, p2.x) && realClose(p1.y, p2.y)
+}
 func realCloseAngle(f1 float64, f2 float64) bool {
 	d := math.Abs(math.Mod(f1, 2*math.Pi) - math.Mod(f2, 2*math.Pi))
 	return d < epsilon || (d > 2*math.Pi-epsilon && d < 2*math.Pi+epsilon) || d > 4*math.Pi-epsilon
@@ -297,6 +444,46 @@ func between(f1 float64, f2 float64, f3 float64) bool {
 func Shift(dx float64, dy float64, gv Value) Value {
 	return gv.shift(dx, dy)
 }
+func Scale(sx float64, sy float64, gv Value) Value {
+	if sx == 0 || sy == 0 {
+		panic("Scale factors must not be zero")
+	}
+	return gv.scale(sx, sy)
+}
+func Rotate(theta float64, gv Value) Value {
+	return gv.rotate(theta)
+}
+func RotateAround(pivot Point, theta float64, gv Value) Value {
+	return gv.shift(-pivot.x, -pivot.y).rotate(theta).shift(pivot.x, pivot.y)
+}
+func Reflect(axis Line, gv Value) Value {
+	// move axis onto the x-axis, mirror there and move it back
+	dx, dy := axis.d*axis.sin, axis.d*axis.cos
+	angle := axis.Angle()
+	return gv.shift(-dx, -dy).rotate(angle).mirror(1, -1).rotate(-angle).shift(dx, dy)
+}
 func Intersect(gv1 Value, gv2 Value) Value {
 	return gv1.intersect(gv2)
 }
+
+// IntersectUntilNowhere intersects v with others in order and stops as soon
+// as the running intersection is Nowhere. It returns the intersection and
+// the index of the value that made it Nowhere, or -1.
+func IntersectUntilNowhere(v Value, others []Value) (Value, int) {
+	for i, o := range others {
+		v = v.intersect(o)
+		if _, ok := v.(nowhere); ok {
+			return v, i
+		}
+	}
+	return v, -1
+}
+func MirrorX(gv Value) Value {
+	return gv.mirror(1, -1)
+}
+func MirrorY(gv Value) Value {
+	return gv.mirror(-1, 1)
+}
+func MirrorOrigin(gv Value) Value {
+	return gv.mirror(-1, -1)
+}
@@ -0,0 +1,54 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// Group is a node of a scene graph: values and subgroups given in the
+// coordinates of a local frame. Sharing a group between several parents
+// places copies of the same assembly.
+type Group struct {
+	frame    Frame
+	values   []Value
+	children []Group
+}
+
+/* Group */
+func NewGroup(frame Frame, values []Value, children []Group) Group {
+	return Group{frame, values, children}
+}
+
+// Flatten returns all values of g and its subgroups in the coordinates of
+// g's parent, i.e. in world coordinates for the root of a scene.
+func (g Group) Flatten() []Value {
+	var result []Value
+	for _, v := range g.values {
+		result = append(result, g.frame.ToWorld(v))
+	}
+	for _, c := range g.children {
+		for _, v := range c.Flatten() {
+			result = append(result, g.frame.ToWorld(v))
+		}
+	}
+	return result
+}
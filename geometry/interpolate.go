@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Interpolate returns the value a fraction t of the way from a to b, which
+// must be of the same kind: Points, LineSegments, Circles, Rects,
+// Triangles, Paths with as many points, or Polygons. Polygon vertices are
+// matched up after subdividing the polygon with fewer of them.
+func Interpolate(a Value, b Value, t float64) (Value, error) {
+	switch va := a.(type) {
+	case Point:
+		if vb, ok := b.(Point); ok {
+			return lerp(va, vb, t), nil
+		}
+	case LineSegment:
+		if vb, ok := b.(LineSegment); ok {
+			p := lerp(Point{va.x1, va.y1}, Point{vb.x1, vb.y1}, t)
+			q := lerp(Point{va.x2, va.y2}, Point{vb.x2, vb.y2}, t)
+			return NewLineSegment(p.x, p.y, q.x, q.y), nil
+		}
+	case Circle:
+		if vb, ok := b.(Circle); ok {
+			return NewCircle(va.x+t*(vb.x-va.x), va.y+t*(vb.y-va.y), va.r+t*(vb.r-va.r)), nil
+		}
+	case Rect:
+		if vb, ok := b.(Rect); ok {
+			p := lerp(Point{va.minX, va.minY}, Point{vb.minX, vb.minY}, t)
+			q := lerp(Point{va.maxX, va.maxY}, Point{vb.maxX, vb.maxY}, t)
+			return NewRect(p.x, p.y, q.x, q.y), nil
+		}
+	case Triangle:
+		if vb, ok := b.(Triangle); ok {
+			p, q, r := lerp(va.a, vb.a, t), lerp(va.b, vb.b, t), lerp(va.c, vb.c, t)
+			return newTriangle(p, q, r), nil
+		}
+	case Path:
+		if vb, ok := b.(Path); ok && len(va.pts) == len(vb.pts) {
+			pts := make([]Point, len(va.pts))
+			for i := range pts {
+				pts[i] = lerp(va.pts[i], vb.pts[i], t)
+			}
+			return Path{pts}, nil
+		}
+	case Polygon:
+		if vb, ok := b.(Polygon); ok {
+			pa, pb := matchVertices(va, vb)
+			pts := make([]Point, len(pa))
+			for i := range pts {
+				pts[i] = lerp(pa[i], pb[i], t)
+			}
+			return Polygon{pts}, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot interpolate between %s and %s", kind(a), kind(b))
+}
+
+// matchVertices returns the counterclockwise vertices of a and b, the
+// shorter list subdivided to the length of the longer one, and the second
+// list turned so the summed squared distances of matched vertices are
+// smallest.
+func matchVertices(a Polygon, b Polygon) ([]Point, []Point) {
+	pa := append([]Point(nil), a.counterclockwise().pts...)
+	pb := append([]Point(nil), b.counterclockwise().pts...)
+	for len(pa) < len(pb) {
+		pa = subdivideLongestEdge(pa)
+	}
+	for len(pb) < len(pa) {
+		pb = subdivideLongestEdge(pb)
+	}
+	n := len(pa)
+	best, bestCost := 0, math.Inf(1)
+	for k := 0; k < n; k++ {
+		cost := 0.0
+		for i := range pa {
+			q := pb[(i+k)%n]
+			cost += (pa[i].x-q.x)*(pa[i].x-q.x) + (pa[i].y-q.y)*(pa[i].y-q.y)
+		}
+		if cost < bestCost {
+			best, bestCost = k, cost
+		}
+	}
+	return pa, append(append([]Point(nil), pb[best:]...), pb[:best]...)
+}
+
+// subdivideLongestEdge inserts the midpoint of the longest edge of the
+// closed polygon pts.
+func subdivideLongestEdge(pts []Point) []Point {
+	longest, length := 0, -1.0
+	for i, p := range pts {
+		q := pts[(i+1)%len(pts)]
+		if l := math.Hypot(q.x-p.x, q.y-p.y); l > length {
+			longest, length = i, l
+		}
+	}
+	mid := lerp(pts[longest], pts[(longest+1)%len(pts)], 0.5)
+	result := append([]Point(nil), pts[:longest+1]...)
+	result = append(result, mid)
+	return append(result, pts[longest+1:]...)
+}
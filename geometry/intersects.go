@@ -0,0 +1,87 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// Intersects reports whether a and b have a point in common. It answers
+// from bounding boxes and orientation tests where it can and computes the
+// intersection only for the remaining kinds.
+func Intersects(a Value, b Value) bool {
+	for _, v := range []Value{a, b} {
+		switch v.(type) {
+		case nowhere:
+			return false
+		case everywhere:
+			return !isNowhere(a) && !isNowhere(b)
+		}
+	}
+	aMinX, aMinY, aMaxX, aMaxY, aOk := valueBounds(a)
+	bMinX, bMinY, bMaxX, bMaxY, bOk := valueBounds(b)
+	if aOk && bOk && (aMinX > bMaxX+epsilon || bMinX > aMaxX+epsilon || aMinY > bMaxY+epsilon || bMinY > aMaxY+epsilon) {
+		return false
+	}
+	switch va := a.(type) {
+	case LineSegment:
+		switch vb := b.(type) {
+		case LineSegment:
+			return segmentsMeet(va, vb)
+		case Line:
+			return lineMeetsSegment(vb, va)
+		}
+	case Line:
+		if vb, ok := b.(LineSegment); ok {
+			return lineMeetsSegment(va, vb)
+		}
+	}
+	return !isNowhere(a.intersect(b))
+}
+
+// side is the sign of x, 0 within epsilon.
+func side(x float64) int {
+	switch {
+	case x > epsilon:
+		return 1
+	case x < -epsilon:
+		return -1
+	}
+	return 0
+}
+func lineMeetsSegment(ln Line, ls LineSegment) bool {
+	return side(ln.sin*ls.x1+ln.cos*ls.y1-ln.d)*side(ln.sin*ls.x2+ln.cos*ls.y2-ln.d) <= 0
+}
+func segmentsMeet(s1 LineSegment, s2 LineSegment) bool {
+	p1, p2 := Point{s1.x1, s1.y1}, Point{s1.x2, s1.y2}
+	q1, q2 := Point{s2.x1, s2.y1}, Point{s2.x2, s2.y2}
+	o1, o2 := side(Orient2D(p1, p2, q1)), side(Orient2D(p1, p2, q2))
+	o3, o4 := side(Orient2D(q1, q2, p1)), side(Orient2D(q1, q2, p2))
+	if segmentsCross(s1, s2) {
+		return true
+	}
+	// an end point on the other segment
+	within := func(a Point, b Point, p Point) bool {
+		return between(a.x, p.x, b.x) && between(a.y, p.y, b.y)
+	}
+	return (o1 == 0 && within(p1, p2, q1)) || (o2 == 0 && within(p1, p2, q2)) ||
+		(o3 == 0 && within(q1, q2, p1)) || (o4 == 0 && within(q1, q2, p2))
+}
@@ -0,0 +1,191 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// marshalValue returns the printed form of gv, which is JSON unless a
+// coordinate is infinite or NaN.
+func marshalValue(gv Value) ([]byte, error) {
+	b := []byte(gv.GoString())
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("cannot marshal %s", b)
+	}
+	return b, nil
+}
+
+func (nw nowhere) MarshalJSON() ([]byte, error)       { return marshalValue(nw) }
+func (ew everywhere) MarshalJSON() ([]byte, error)    { return marshalValue(ew) }
+func (p Point) MarshalJSON() ([]byte, error)          { return marshalValue(p) }
+func (ln Line) MarshalJSON() ([]byte, error)          { return marshalValue(ln) }
+func (ls LineSegment) MarshalJSON() ([]byte, error)   { return marshalValue(ls) }
+func (r Ray) MarshalJSON() ([]byte, error)            { return marshalValue(r) }
+func (c Circle) MarshalJSON() ([]byte, error)         { return marshalValue(c) }
+func (a Arc) MarshalJSON() ([]byte, error)            { return marshalValue(a) }
+func (b Bezier) MarshalJSON() ([]byte, error)         { return marshalValue(b) }
+func (pa Path) MarshalJSON() ([]byte, error)          { return marshalValue(pa) }
+func (pg Polygon) MarshalJSON() ([]byte, error)       { return marshalValue(pg) }
+func (r Rect) MarshalJSON() ([]byte, error)           { return marshalValue(r) }
+func (t Triangle) MarshalJSON() ([]byte, error)       { return marshalValue(t) }
+func (ps pointSet) MarshalJSON() ([]byte, error)      { return marshalValue(ps) }
+func (cc compoundCurve) MarshalJSON() ([]byte, error) { return marshalValue(cc) }
+func (cl collection) MarshalJSON() ([]byte, error)    { return marshalValue(cl) }
+
+// UnmarshalValue reads a value back from its JSON form as written by
+// MarshalJSON or printed by GoString.
+func UnmarshalValue(data []byte) (v Value, err error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	// constructors panic on invalid coordinates
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	return decodeValue(raw)
+}
+func decodeValue(raw interface{}) (Value, error) {
+	switch rt := raw.(type) {
+	case string:
+		switch rt {
+		case "Nowhere":
+			return Nowhere, nil
+		case "Everywhere":
+			return Everywhere, nil
+		}
+	case map[string]interface{}:
+		if len(rt) != 1 {
+			break
+		}
+		for kind, args := range rt {
+			ls, ok := args.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s expects a list", kind)
+			}
+			return decodeKind(kind, ls)
+		}
+	}
+	return nil, fmt.Errorf("not a value: %v", raw)
+}
+func decodeKind(kind string, args []interface{}) (Value, error) {
+	switch kind {
+	case "Path", "Polygon":
+		var xy []float64
+		for _, arg := range args {
+			p, err := decodeFloats(arg, 2)
+			if err != nil {
+				return nil, err
+			}
+			xy = append(xy, p...)
+		}
+		if kind == "Path" {
+			return NewPath(xy...), nil
+		}
+		return NewPolygon(xy...), nil
+	case "PointSet", "CompoundCurve", "Collection":
+		vs := make([]Value, len(args))
+		for i, arg := range args {
+			v, err := decodeValue(arg)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = v
+		}
+		switch kind {
+		case "PointSet":
+			var pts []Point
+			for _, v := range vs {
+				p, ok := v.(Point)
+				if !ok {
+					return nil, fmt.Errorf("PointSet expects Points")
+				}
+				pts = append(pts, p)
+			}
+			return newPointSet(pts), nil
+		case "CompoundCurve":
+			parts := make([]Curve, len(vs))
+			for i, v := range vs {
+				c, ok := v.(Curve)
+				if !ok {
+					return nil, fmt.Errorf("CompoundCurve expects curves")
+				}
+				parts[i] = c
+			}
+			return newCompoundCurve(parts), nil
+		}
+		return NewCollection(vs...), nil
+	}
+	arity := map[string]int{"Point": 2, "Line": 2, "LineSegment": 4, "Ray": 3, "Circle": 3, "Arc": 5,
+		"Rect": 4, "Triangle": 6, "QuadraticBezier": 6, "CubicBezier": 8}
+	n, ok := arity[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %s", kind)
+	}
+	f, err := decodeFloats(args, n)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", kind, err)
+	}
+	switch kind {
+	case "Point":
+		return NewPoint(f[0], f[1]), nil
+	case "Line":
+		return NewLine(f[0], f[1]), nil
+	case "LineSegment":
+		return NewLineSegment(f[0], f[1], f[2], f[3]), nil
+	case "Ray":
+		return NewRay(f[0], f[1], f[2]), nil
+	case "Circle":
+		return NewCircle(f[0], f[1], f[2]), nil
+	case "Arc":
+		return NewArc(f[0], f[1], f[2], f[3], f[4]), nil
+	case "Rect":
+		return NewRect(f[0], f[1], f[2], f[3]), nil
+	case "Triangle":
+		return NewTriangle(f[0], f[1], f[2], f[3], f[4], f[5]), nil
+	case "QuadraticBezier":
+		return NewQuadraticBezier(f[0], f[1], f[2], f[3], f[4], f[5]), nil
+	}
+	return NewCubicBezier(f[0], f[1], f[2], f[3], f[4], f[5], f[6], f[7]), nil
+}
+
+// decodeFloats reads a list of n numbers.
+func decodeFloats(raw interface{}, n int) ([]float64, error) {
+	ls, ok := raw.([]interface{})
+	if !ok || len(ls) != n {
+		return nil, fmt.Errorf("expected %d numbers, got %v", n, raw)
+	}
+	f := make([]float64, n)
+	for i := range ls {
+		if f[i], ok = ls[i].(float64); !ok {
+			return nil, fmt.Errorf("expected a number, got %v", ls[i])
+		}
+	}
+	return f, nil
+}
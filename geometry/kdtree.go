@@ -0,0 +1,166 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// KDTree holds points for nearest neighbour and radius queries.
+type KDTree struct {
+	root *kdNode
+	size int
+}
+
+type kdNode struct {
+	p     Point
+	axis  int // 0 splits on x, 1 on y
+	left  *kdNode
+	right *kdNode
+}
+
+// NewKDTree builds a balanced tree from pts.
+func NewKDTree(pts []Point) *KDTree {
+	pts = append([]Point(nil), pts...)
+	return &KDTree{buildKDNode(pts, 0), len(pts)}
+}
+
+func buildKDNode(pts []Point, axis int) *kdNode {
+	if len(pts) == 0 {
+		return nil
+	}
+	sort.Slice(pts, func(i, j int) bool { return kdCoord(pts[i], axis) < kdCoord(pts[j], axis) })
+	m := len(pts) / 2
+	return &kdNode{pts[m], axis, buildKDNode(pts[:m], 1-axis), buildKDNode(pts[m+1:], 1-axis)}
+}
+
+func kdCoord(p Point, axis int) float64 {
+	if axis == 0 {
+		return p.x
+	}
+	return p.y
+}
+
+// Len returns the number of points in the tree.
+func (t *KDTree) Len() int {
+	return t.size
+}
+
+// Insert adds p to the tree. Repeated insertion can unbalance the tree;
+// rebuild with NewKDTree after large batches.
+func (t *KDTree) Insert(p Point) {
+	t.size++
+	link := &t.root
+	axis := 0
+	for *link != nil {
+		n := *link
+		if kdCoord(p, n.axis) < kdCoord(n.p, n.axis) {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+		axis = 1 - n.axis
+	}
+	*link = &kdNode{p: p, axis: axis}
+}
+
+// Nearest returns the k points closest to p, nearest first.
+func (t *KDTree) Nearest(p Point, k int) []Point {
+	if k <= 0 {
+		return nil
+	}
+	h := &kdHeap{}
+	t.root.nearest(p, k, h)
+	result := make([]Point, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(kdCandidate).p
+	}
+	return result
+}
+
+func (n *kdNode) nearest(p Point, k int, h *kdHeap) {
+	if n == nil {
+		return
+	}
+	d := math.Hypot(n.p.x-p.x, n.p.y-p.y)
+	if h.Len() < k {
+		heap.Push(h, kdCandidate{n.p, d})
+	} else if d < (*h)[0].dist {
+		(*h)[0] = kdCandidate{n.p, d}
+		heap.Fix(h, 0)
+	}
+	diff := kdCoord(p, n.axis) - kdCoord(n.p, n.axis)
+	near, far := n.left, n.right
+	if diff >= 0 {
+		near, far = n.right, n.left
+	}
+	near.nearest(p, k, h)
+	if h.Len() < k || math.Abs(diff) < (*h)[0].dist {
+		far.nearest(p, k, h)
+	}
+}
+
+// Within returns all points at distance at most r from p.
+func (t *KDTree) Within(p Point, r float64) []Point {
+	var result []Point
+	t.root.within(p, r, &result)
+	return result
+}
+
+func (n *kdNode) within(p Point, r float64, result *[]Point) {
+	if n == nil {
+		return
+	}
+	if math.Hypot(n.p.x-p.x, n.p.y-p.y) <= r+epsilon {
+		*result = append(*result, n.p)
+	}
+	diff := kdCoord(p, n.axis) - kdCoord(n.p, n.axis)
+	if diff-r < epsilon {
+		n.left.within(p, r, result)
+	}
+	if diff+r > -epsilon {
+		n.right.within(p, r, result)
+	}
+}
+
+// kdHeap is a max-heap of the best candidates found so far.
+type kdCandidate struct {
+	p    Point
+	dist float64
+}
+type kdHeap []kdCandidate
+
+func (h kdHeap) Len() int            { return len(h) }
+func (h kdHeap) Less(i, j int) bool  { return h[i].dist > h[j].dist }
+func (h kdHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *kdHeap) Push(x interface{}) { *h = append(*h, x.(kdCandidate)) }
+func (h *kdHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,115 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+// Package laws checks the algebraic laws the geometry operations are meant
+// to obey against randomly generated values and reports counterexamples.
+package laws
+
+import (
+	"fmt"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"math/rand"
+)
+
+// Generator produces a random value from r.
+type Generator func(r *rand.Rand) geometry.Value
+
+// Counterexample is one violation of a law.
+type Counterexample struct {
+	Law    string
+	Inputs []geometry.Value
+	Got    geometry.Value
+	Want   geometry.Value
+	Panic  string // set if an operation panicked instead
+}
+
+func (c Counterexample) String() string {
+	if c.Panic != "" {
+		return fmt.Sprintf("%s: inputs %#v panicked: %s", c.Law, c.Inputs, c.Panic)
+	}
+	return fmt.Sprintf("%s: inputs %#v gave %#v, want %#v", c.Law, c.Inputs, c.Got, c.Want)
+}
+
+type law struct {
+	name  string
+	arity int
+	// check returns the two sides of the law
+	check func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value)
+}
+
+var laws = []law{
+	{"intersection is commutative", 2, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Intersect(vs[0], vs[1]), geometry.Intersect(vs[1], vs[0])
+	}},
+	{"intersection is associative", 3, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Intersect(geometry.Intersect(vs[0], vs[1]), vs[2]), geometry.Intersect(vs[0], geometry.Intersect(vs[1], vs[2]))
+	}},
+	{"intersection is idempotent", 1, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Intersect(vs[0], vs[0]), vs[0]
+	}},
+	{"Everywhere is the identity", 1, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Intersect(vs[0], geometry.Everywhere), vs[0]
+	}},
+	{"Nowhere absorbs", 1, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Intersect(vs[0], geometry.Nowhere), geometry.Nowhere
+	}},
+	{"shifts add up", 1, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Shift(dy, dx, geometry.Shift(dx, dy, vs[0])), geometry.Shift(dx+dy, dx+dy, vs[0])
+	}},
+	{"shift distributes over intersection", 2, func(vs []geometry.Value, dx float64, dy float64) (geometry.Value, geometry.Value) {
+		return geometry.Shift(dx, dy, geometry.Intersect(vs[0], vs[1])), geometry.Intersect(geometry.Shift(dx, dy, vs[0]), geometry.Shift(dx, dy, vs[1]))
+	}},
+}
+
+// Check tests every law n times with values from gen and returns the
+// violations. Results are compared with absolute tolerance tol.
+func Check(gen Generator, n int, seed int64, tol float64) []Counterexample {
+	r := rand.New(rand.NewSource(seed))
+	var result []Counterexample
+	for _, l := range laws {
+		for i := 0; i < n; i++ {
+			vs := make([]geometry.Value, l.arity)
+			for k := range vs {
+				vs[k] = gen(r)
+			}
+			dx, dy := r.NormFloat64(), r.NormFloat64()
+			if c, ok := checkLaw(l, vs, dx, dy, tol); !ok {
+				result = append(result, c)
+			}
+		}
+	}
+	return result
+}
+
+func checkLaw(l law, vs []geometry.Value, dx float64, dy float64, tol float64) (c Counterexample, ok bool) {
+	c = Counterexample{Law: l.name, Inputs: vs}
+	defer func() {
+		if r := recover(); r != nil {
+			c.Panic = fmt.Sprint(r)
+			ok = false
+		}
+	}()
+	c.Got, c.Want = l.check(vs, dx, dy)
+	return c, geometry.ApproxEqual(c.Got, c.Want, tol)
+}
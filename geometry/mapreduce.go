@@ -0,0 +1,249 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// MapReduceOption configures MapReduce.
+type MapReduceOption func(*mapReduceConfig)
+
+type mapReduceConfig struct {
+	ctx       context.Context
+	workers   int
+	chunkSize int
+	tree      bool
+	progress  *Progress
+}
+
+// WithContext stops MapReduce early when ctx is done.
+func WithContext(ctx context.Context) MapReduceOption {
+	return func(c *mapReduceConfig) { c.ctx = ctx }
+}
+
+// WithWorkers bounds the number of goroutines, by default GOMAXPROCS.
+func WithWorkers(n int) MapReduceOption {
+	return func(c *mapReduceConfig) { c.workers = n }
+}
+
+// WithChunkSize sets how many values a worker takes at a time, by default
+// 1024.
+func WithChunkSize(n int) MapReduceOption {
+	return func(c *mapReduceConfig) { c.chunkSize = n }
+}
+
+// WithTreeOrder makes MapReduce combine the mapped values pairwise in a
+// balanced tree: the first and the second half of the values are reduced,
+// in parallel, and their results combined. The order depends only on the
+// number of values, so results are reproducible even for a reduceFn that is
+// only associative up to rounding.
+func WithTreeOrder() MapReduceOption {
+	return func(c *mapReduceConfig) { c.tree = true }
+}
+
+// WithProgress adds a step to p for every value MapReduce, ShiftAll or
+// IntersectAll has taken in, so p can be created with the number of values
+// as its total.
+func WithProgress(p *Progress) MapReduceOption {
+	return func(c *mapReduceConfig) { c.progress = p }
+}
+
+// MapReduce applies mapFn to every value and combines the results with
+// reduceFn. Chunks of values are mapped and reduced by a bounded pool of
+// workers and the chunk results are reduced in order, so an associative
+// reduceFn gives the same result as a sequential fold. Without values the
+// result is nil. A nil mapFn keeps the values as they are.
+func MapReduce(values []Value, mapFn func(Value) Value, reduceFn func(Value, Value) Value, opts ...MapReduceOption) (Value, error) {
+	if cfg := newMapReduceConfig(opts); cfg.tree {
+		mapped := values
+		if mapFn != nil {
+			mapped = make([]Value, len(values))
+			if _, err := mapChunks(values, func(offset int, chunk []Value) Value {
+				for i, v := range chunk {
+					mapped[offset+i] = mapFn(v)
+				}
+				return nil
+			}, opts); err != nil {
+				return nil, err
+			}
+		}
+		if len(mapped) == 0 {
+			return nil, cfg.ctx.Err()
+		}
+		if mapFn == nil && cfg.progress != nil {
+			// without mapping, the values are taken in by the reduction
+			p, reduce := cfg.progress, reduceFn
+			p.Add(1)
+			reduceFn = func(a Value, b Value) Value {
+				defer p.Add(1)
+				return reduce(a, b)
+			}
+		}
+		result := treeReduce(mapped, reduceFn, make(chan struct{}, cfg.workers-1))
+		return result, cfg.ctx.Err()
+	}
+	partial, err := mapChunks(values, func(offset int, chunk []Value) Value {
+		var acc Value
+		for i, v := range chunk {
+			if mapFn != nil {
+				v = mapFn(v)
+			}
+			if i == 0 {
+				acc = v
+			} else {
+				acc = reduceFn(acc, v)
+			}
+		}
+		return acc
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var result Value
+	for i, v := range partial {
+		if i == 0 {
+			result = v
+		} else {
+			result = reduceFn(result, v)
+		}
+	}
+	return result, nil
+}
+
+// ShiftAll shifts every value by (dx, dy) in parallel.
+func ShiftAll(values []Value, dx float64, dy float64, opts ...MapReduceOption) ([]Value, error) {
+	result := make([]Value, len(values))
+	_, err := mapChunks(values, func(offset int, chunk []Value) Value {
+		for i, v := range chunk {
+			result[offset+i] = v.shift(dx, dy)
+		}
+		return nil
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// IntersectAll intersects all values, Everywhere if there are none. With
+// WithTreeOrder they are intersected in a balanced tree. The error is only
+// set if the context of WithContext is done first, so without options it is
+// always nil.
+func IntersectAll(values []Value, opts ...MapReduceOption) (Value, error) {
+	if len(values) == 0 {
+		return Everywhere, nil
+	}
+	return MapReduce(values, nil, Intersect, opts...)
+}
+func newMapReduceConfig(opts []MapReduceOption) mapReduceConfig {
+	cfg := mapReduceConfig{context.Background(), runtime.GOMAXPROCS(0), 1024, false, nil}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+	if cfg.chunkSize < 1 {
+		cfg.chunkSize = 1
+	}
+	return cfg
+}
+
+// mapChunks runs f on consecutive chunks of values, together with the index
+// of their first value, and returns the results in chunk order.
+func mapChunks(values []Value, f func(int, []Value) Value, opts []MapReduceOption) ([]Value, error) {
+	cfg := newMapReduceConfig(opts)
+	n := (len(values) + cfg.chunkSize - 1) / cfg.chunkSize
+	results := make([]Value, n)
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				end := (i + 1) * cfg.chunkSize
+				if end > len(values) {
+					end = len(values)
+				}
+				results[i] = f(i*cfg.chunkSize, values[i*cfg.chunkSize:end])
+				if cfg.progress != nil {
+					cfg.progress.Add(int64(end - i*cfg.chunkSize))
+				}
+			}
+		}()
+	}
+	var err error
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case work <- i:
+		case <-cfg.ctx.Done():
+			err = cfg.ctx.Err()
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	if err == nil {
+		err = cfg.ctx.Err()
+	}
+	return results, err
+}
+
+// treeReduce reduces the halves of values and combines their results. The
+// first half runs in a new goroutine while sem has room; a panic there is
+// raised again in the caller.
+func treeReduce(values []Value, reduceFn func(Value, Value) Value, sem chan struct{}) Value {
+	if len(values) == 1 {
+		return values[0]
+	}
+	mid := len(values) / 2
+	select {
+	case sem <- struct{}{}:
+		var left Value
+		var failure interface{}
+		done := make(chan struct{})
+		go func() {
+			defer func() {
+				failure = recover()
+				<-sem
+				close(done)
+			}()
+			left = treeReduce(values[:mid], reduceFn, sem)
+		}()
+		right := treeReduce(values[mid:], reduceFn, sem)
+		<-done
+		if failure != nil {
+			panic(failure)
+		}
+		return reduceFn(left, right)
+	default:
+		return reduceFn(treeReduce(values[:mid], reduceFn, sem), treeReduce(values[mid:], reduceFn, sem))
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// MergeSegments joins segments lying on a common line that overlap or
+// touch into maximal segments and drops duplicates. End points within tol of
+// a line count as on it, and gaps up to tol are closed.
+func MergeSegments(segs []LineSegment, tol float64) []Value {
+	var groups []*mergeGroup
+	var dots []Point
+	for _, s := range segs {
+		p, q := Point{s.x1, s.y1}, Point{s.x2, s.y2}
+		l := math.Hypot(q.x-p.x, q.y-p.y)
+		if l <= tol {
+			dots = append(dots, p)
+			continue
+		}
+		var g *mergeGroup
+		for _, cand := range groups {
+			if cand.distance(p) <= tol && cand.distance(q) <= tol {
+				g = cand
+				break
+			}
+		}
+		if g == nil {
+			g = &mergeGroup{o: p, dir: Point{(q.x - p.x) / l, (q.y - p.y) / l}}
+			groups = append(groups, g)
+		}
+		tp, tq := g.param(p), g.param(q)
+		g.intervals = append(g.intervals, [2]float64{math.Min(tp, tq), math.Max(tp, tq)})
+	}
+	var result []Value
+	for _, g := range groups {
+		sort.Slice(g.intervals, func(i, j int) bool { return g.intervals[i][0] < g.intervals[j][0] })
+		cur := g.intervals[0]
+		for _, iv := range g.intervals[1:] {
+			if iv[0] <= cur[1]+tol {
+				cur[1] = math.Max(cur[1], iv[1])
+				continue
+			}
+			result = append(result, g.segment(cur))
+			cur = iv
+		}
+		result = append(result, g.segment(cur))
+	}
+	// degenerate segments survive as points unless something covers them
+	var pts []Point
+	for _, p := range dots {
+		covered := false
+		for _, g := range groups {
+			covered = covered || g.covers(p, tol)
+		}
+		if !covered {
+			pts = addPoint(pts, p)
+		}
+	}
+	for _, p := range pts {
+		result = append(result, p)
+	}
+	return result
+}
+
+// mergeGroup collects the parameter intervals of segments on the line
+// through o with unit direction dir.
+type mergeGroup struct {
+	o         Point
+	dir       Point
+	intervals [][2]float64
+}
+
+func (g *mergeGroup) distance(p Point) float64 {
+	return math.Abs((p.x-g.o.x)*g.dir.y - (p.y-g.o.y)*g.dir.x)
+}
+func (g *mergeGroup) param(p Point) float64 {
+	return (p.x-g.o.x)*g.dir.x + (p.y-g.o.y)*g.dir.y
+}
+func (g *mergeGroup) covers(p Point, tol float64) bool {
+	if g.distance(p) > tol {
+		return false
+	}
+	t := g.param(p)
+	for _, iv := range g.intervals {
+		if t >= iv[0]-tol && t <= iv[1]+tol {
+			return true
+		}
+	}
+	return false
+}
+func (g *mergeGroup) segment(iv [2]float64) Value {
+	return NewLineSegment(g.o.x+iv[0]*g.dir.x, g.o.y+iv[0]*g.dir.y, g.o.x+iv[1]*g.dir.x, g.o.y+iv[1]*g.dir.y)
+}
@@ -0,0 +1,155 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// maximum distance between a curve and the polyline approximating it
+// when offsetting
+const flattenTolerance = 0.001
+
+// longest miter, relative to the offset distance, before a miter join
+// falls back to a bevel
+const miterLimit = 4
+
+// Join selects how OffsetCurveJoin connects the offset pieces at the
+// outside of a corner.
+type Join int
+
+const (
+	MiterJoin Join = iota
+	RoundJoin
+	BevelJoin
+)
+
+// OffsetCurve returns the curve at distance d from c using miter joins.
+// Positive d offsets to the left of the direction of travel.
+func OffsetCurve(c Curve, d float64) Curve {
+	return OffsetCurveJoin(c, d, MiterJoin)
+}
+
+// OffsetCurveJoin is OffsetCurve with a choice of corner treatment. Curved
+// pieces are flattened first, so the result is a polyline.
+func OffsetCurveJoin(c Curve, d float64, join Join) Curve {
+	if ls, ok := c.(LineSegment); ok {
+		nx, ny := normal(Point{ls.x1, ls.y1}, Point{ls.x2, ls.y2})
+		return LineSegment{ls.x1 + d*nx, ls.y1 + d*ny, ls.x2 + d*nx, ls.y2 + d*ny}
+	}
+	// an arc runs counterclockwise, so its left side faces the center
+	if a, ok := c.(Arc); ok && a.r-d > epsilon {
+		return Arc{a.x, a.y, a.r - d, a.start, a.sweep}
+	}
+	return offsetPolyline(curvePoints(c), d, join)
+}
+
+// curvePoints returns the vertices of a polyline approximating c.
+func curvePoints(c Curve) []Point {
+	var pts []Point
+	switch cv := c.(type) {
+	case LineSegment:
+		pts = []Point{{cv.x1, cv.y1}, {cv.x2, cv.y2}}
+	case Bezier:
+		pts = cv.Flatten(flattenTolerance)
+	case Path:
+		pts = cv.pts
+	case Arc:
+		// chords of this angle stay within flattenTolerance of the arc
+		step := 2 * math.Acos(math.Max(1-flattenTolerance/cv.r, -1))
+		n := int(math.Ceil(cv.sweep / step))
+		for i := 0; i <= n; i++ {
+			pts = append(pts, cv.At(float64(i)/float64(n)))
+		}
+	case compoundCurve:
+		for _, part := range cv.parts {
+			ps := curvePoints(part)
+			// line segments do not keep their direction
+			if n := len(pts); n > 0 && !realClosePoint(ps[0], pts[n-1]) && realClosePoint(ps[len(ps)-1], pts[n-1]) {
+				for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+					ps[i], ps[j] = ps[j], ps[i]
+				}
+			}
+			pts = append(pts, ps...)
+		}
+	default:
+		for i := 0; i <= curveSamples; i++ {
+			pts = append(pts, c.At(float64(i)/curveSamples))
+		}
+	}
+	// drop repeated vertices
+	var result []Point
+	for _, p := range pts {
+		if len(result) == 0 || !realClosePoint(p, result[len(result)-1]) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func offsetPolyline(pts []Point, d float64, join Join) Curve {
+	if len(pts) < 2 {
+		panic("Cannot offset a curve of zero length")
+	}
+	n := len(pts) - 1
+	nx := make([]float64, n)
+	ny := make([]float64, n)
+	for i := 0; i < n; i++ {
+		nx[i], ny[i] = normal(pts[i], pts[i+1])
+	}
+	result := []Point{{pts[0].x + d*nx[0], pts[0].y + d*ny[0]}}
+	for i := 1; i < n; i++ {
+		v := pts[i]
+		a := Point{v.x + d*nx[i-1], v.y + d*ny[i-1]}
+		b := Point{v.x + d*nx[i], v.y + d*ny[i]}
+		dot := nx[i-1]*nx[i] + ny[i-1]*ny[i]
+		cross := nx[i-1]*ny[i] - ny[i-1]*nx[i]
+		if realClosePoint(a, b) {
+			result = append(result, a)
+		} else if d*cross > 0 || join == MiterJoin && 2/(1+dot) <= miterLimit*miterLimit {
+			// the offset pieces meet: inside of the corner or a short miter
+			result = append(result, Point{v.x + d*(nx[i-1]+nx[i])/(1+dot), v.y + d*(ny[i-1]+ny[i])/(1+dot)})
+		} else if join == RoundJoin {
+			start := math.Atan2(a.y-v.y, a.x-v.x)
+			sweep := math.Atan2(cross, dot)
+			steps := int(math.Ceil(math.Abs(sweep) / (2 * math.Acos(1-math.Min(flattenTolerance/math.Abs(d), 1)))))
+			for k := 0; k <= steps; k++ {
+				phi := start + sweep*float64(k)/float64(steps)
+				result = append(result, Point{v.x + math.Abs(d)*math.Cos(phi), v.y + math.Abs(d)*math.Sin(phi)})
+			}
+		} else {
+			result = append(result, a, b)
+		}
+	}
+	result = append(result, Point{pts[n].x + d*nx[n-1], pts[n].y + d*ny[n-1]})
+	return Path{result}
+}
+
+// normal returns the unit vector pointing to the left of the direction
+// from p to q.
+func normal(p Point, q Point) (float64, float64) {
+	l := math.Hypot(q.x-p.x, q.y-p.y)
+	return -(q.y - p.y) / l, (q.x - p.x) / l
+}
@@ -0,0 +1,67 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// Polylines approximates the bounded value gv by polylines for drawing.
+// Closed shapes end at their first point and single points become
+// polylines of one point. Unbounded values give nil; intersect them with a
+// Rect first.
+func Polylines(gv Value) [][]Point {
+	switch v := gv.(type) {
+	case Point:
+		return [][]Point{{v}}
+	case pointSet:
+		result := make([][]Point, len(v.pts))
+		for i, p := range v.pts {
+			result[i] = []Point{p}
+		}
+		return result
+	case Curve:
+		return [][]Point{curvePoints(v)}
+	case Circle:
+		return [][]Point{curvePoints(Arc{v.x, v.y, v.r, 0, 2 * math.Pi})}
+	case Polygon:
+		return [][]Point{v.boundary().pts}
+	case Rect:
+		return [][]Point{v.toPolygon().boundary().pts}
+	case Triangle:
+		return [][]Point{v.toPolygon().boundary().pts}
+	case collection:
+		var result [][]Point
+		for _, m := range v.vs {
+			pls := Polylines(m)
+			if pls == nil {
+				return nil
+			}
+			result = append(result, pls...)
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// PairwiseIntersections intersects every pair of values in vs and returns
+// the intersections that are not Nowhere, ordered by the indices of the
+// pairs.
+func PairwiseIntersections(vs []Value) []Value {
+	var result []Value
+	for i := range vs {
+		for j := i + 1; j < len(vs); j++ {
+			r := vs[i].intersect(vs[j])
+			if _, ok := r.(nowhere); !ok {
+				result = append(result, r)
+			}
+		}
+	}
+	return result
+}
+
+// IntersectEach intersects a[i] with b[i] for every i into one preallocated
+// slice, Nowhere included. a and b must have the same length. Unlike
+// PairwiseIntersections it pairs values of two slices by index instead of
+// every two values of one slice.
+func IntersectEach(a []Value, b []Value) []Value {
+	if len(a) != len(b) {
+		panic("IntersectEach needs slices of the same length")
+	}
+	result := make([]Value, len(a))
+	for i := range a {
+		result[i] = a[i].intersect(b[i])
+	}
+	return result
+}
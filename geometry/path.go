@@ -0,0 +1,147 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Path is a polyline through two or more points. Unlike lineSegment it
+// keeps the direction in which the points were given.
+type Path struct {
+	pts []Point
+}
+
+/* path */
+// NewPath builds the polyline through the points (xy[0], xy[1]),
+// (xy[2], xy[3]), ... in that order.
+func NewPath(xy ...float64) Path {
+	if len(xy)%2 != 0 {
+		panic("Path needs an even number of coordinates")
+	}
+	if len(xy) < 4 {
+		panic("Path needs at least two points")
+	}
+	pts := make([]Point, len(xy)/2)
+	for i := range pts {
+		pts[i] = Point{xy[2*i], xy[2*i+1]}
+		if i > 0 && realClosePoint(pts[i-1], pts[i]) {
+			panic(fmt.Sprintf("Repeated consecutive points in Path at index %d", i))
+		}
+	}
+	return Path{pts}
+}
+func (pa Path) shift(dx float64, dy float64) Value {
+	pts := make([]Point, len(pa.pts))
+	for i, p := range pa.pts {
+		pts[i] = Point{p.x + dx, p.y + dy}
+	}
+	return Path{pts}
+}
+func (pa Path) mirror(fx float64, fy float64) Value {
+	return Path{mapPoints(pa.pts, func(p Point) Point { return Point{fx * p.x, fy * p.y} })}
+}
+func (pa Path) scale(sx float64, sy float64) Value {
+	return Path{mapPoints(pa.pts, func(p Point) Point { return Point{sx * p.x, sy * p.y} })}
+}
+func (pa Path) rotate(theta float64) Value {
+	return Path{mapPoints(pa.pts, func(p Point) Point { return p.rotate(theta).(Point) })}
+}
+func (pa Path) intersect(other Value) Value {
+	switch other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return pa
+	}
+	var parts []Value
+	for i := 1; i < len(pa.pts); i++ {
+		p, q := pa.pts[i-1], pa.pts[i]
+		parts = append(parts, NewLineSegment(p.x, p.y, q.x, q.y).intersect(other))
+	}
+	return newCollection(parts)
+}
+func (pa Path) GoString() string {
+	s := make([]string, len(pa.pts))
+	for i, p := range pa.pts {
+		s[i] = fmt.Sprintf("[%v,%v]", p.x, p.y)
+	}
+	return fmt.Sprintf("{\"Path\":[%s]}", strings.Join(s, ","))
+}
+func (pa Path) String() string {
+	return "Path" + readablePoints(pa.pts)
+}
+
+/* path as Curve */
+func (pa Path) At(t float64) Point {
+	i, u := pa.locate(t)
+	return lerp(pa.pts[i], pa.pts[i+1], u)
+}
+func (pa Path) Bounds() (float64, float64, float64, float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pa.pts {
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+	return minX, minY, maxX, maxY
+}
+func (pa Path) Split(t float64) (Curve, Curve) {
+	i, u := pa.locate(t)
+	p := lerp(pa.pts[i], pa.pts[i+1], u)
+	left := append([]Point(nil), pa.pts[:i+1]...)
+	if !realClosePoint(pa.pts[i], p) {
+		left = append(left, p)
+	}
+	right := []Point{p}
+	if !realClosePoint(p, pa.pts[i+1]) {
+		right = append(right, pa.pts[i+1])
+	}
+	right = append(right, pa.pts[i+2:]...)
+	return pathOrPoint(left), pathOrPoint(right)
+}
+
+// pathOrPoint returns the Path through pts, or a LineSegment of length zero
+// if the split left only one point, as LineSegment.Split does at its ends.
+func pathOrPoint(pts []Point) Curve {
+	if len(pts) == 1 {
+		return LineSegment{pts[0].x, pts[0].y, pts[0].x, pts[0].y}
+	}
+	return Path{pts}
+}
+
+// locate maps t to the index of a piece and the parameter within it.
+func (pa Path) locate(t float64) (int, float64) {
+	n := float64(len(pa.pts) - 1)
+	i := int(math.Floor(t * n))
+	if i < 0 {
+		i = 0
+	} else if i >= len(pa.pts)-1 {
+		i = len(pa.pts) - 2
+	}
+	return i, t*n - float64(i)
+}
@@ -0,0 +1,121 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pointSet holds two or more distinct points, e.g. the result of
+// intersecting a curve with a line.
+type pointSet struct {
+	pts []Point
+}
+
+// NewPointSet returns the points with the given coordinates as a set:
+// duplicates are merged, and a single point or none give a Point or Nowhere.
+// Intersecting a point set with any value keeps the points on that value.
+func NewPointSet(xy ...float64) Value {
+	if len(xy)%2 != 0 {
+		panic("PointSet needs an even number of coordinates")
+	}
+	pts := make([]Point, len(xy)/2)
+	for i := range pts {
+		pts[i] = Point{xy[2*i], xy[2*i+1]}
+	}
+	return newPointSet(pts)
+}
+
+// newPointSet makes the simplest value covering all points in ps.
+func newPointSet(ps []Point) Value {
+	var pts []Point
+	for _, p := range ps {
+		pts = addPoint(pts, p)
+	}
+	switch len(pts) {
+	case 0:
+		return Nowhere
+	case 1:
+		return pts[0]
+	default:
+		return pointSet{pts}
+	}
+}
+
+// addPoint appends p to ps unless ps already holds a point close to it.
+func addPoint(ps []Point, p Point) []Point {
+	for _, q := range ps {
+		if realClosePoint(p, q) {
+			return ps
+		}
+	}
+	return append(ps, p)
+}
+
+// mapPoints applies f to every point of pts.
+func mapPoints(pts []Point, f func(Point) Point) []Point {
+	result := make([]Point, len(pts))
+	for i, p := range pts {
+		result[i] = f(p)
+	}
+	return result
+}
+
+func (ps pointSet) shift(dx float64, dy float64) Value {
+	pts := make([]Point, len(ps.pts))
+	for i, p := range ps.pts {
+		pts[i] = Point{p.x + dx, p.y + dy}
+	}
+	return pointSet{pts}
+}
+func (ps pointSet) mirror(fx float64, fy float64) Value {
+	return pointSet{mapPoints(ps.pts, func(p Point) Point { return Point{fx * p.x, fy * p.y} })}
+}
+func (ps pointSet) scale(sx float64, sy float64) Value {
+	return pointSet{mapPoints(ps.pts, func(p Point) Point { return Point{sx * p.x, sy * p.y} })}
+}
+func (ps pointSet) rotate(theta float64) Value {
+	return pointSet{mapPoints(ps.pts, func(p Point) Point { return p.rotate(theta).(Point) })}
+}
+func (ps pointSet) intersect(other Value) Value {
+	var pts []Point
+	for _, p := range ps.pts {
+		if _, ok := p.intersect(other).(Point); ok {
+			pts = append(pts, p)
+		}
+	}
+	return newPointSet(pts)
+}
+func (ps pointSet) GoString() string {
+	s := make([]string, len(ps.pts))
+	for i, p := range ps.pts {
+		s[i] = p.GoString()
+	}
+	return fmt.Sprintf("{\"PointSet\":[%s]}", strings.Join(s, ","))
+}
+func (ps pointSet) String() string {
+	return "PointSet" + readablePoints(ps.pts)
+}
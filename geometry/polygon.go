@@ -0,0 +1,323 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Polygon is the region enclosed by a simple polygon given by its vertices
+// in order. The closing edge from the last back to the first vertex is
+// implicit.
+type Polygon struct {
+	pts []Point
+}
+
+/* polygon */
+func NewPolygon(xy ...float64) Polygon {
+	if len(xy)%2 != 0 {
+		panic("Polygon needs an even number of coordinates")
+	}
+	var result []Point
+	for i := 0; i < len(xy); i += 2 {
+		p := Point{xy[i], xy[i+1]}
+		if len(result) == 0 || !realClosePoint(p, result[len(result)-1]) {
+			result = append(result, p)
+		}
+	}
+	if len(result) > 1 && realClosePoint(result[0], result[len(result)-1]) {
+		result = result[:len(result)-1]
+	}
+	if len(result) < 3 {
+		panic("A Polygon needs at least three distinct points")
+	}
+	if len(removeCollinear(result)) < 3 {
+		panic("A Polygon needs points not all on one line")
+	}
+	return Polygon{result}
+}
+
+// RegularPolygon returns the regular n-gon around center whose first vertex
+// lies at angle rotation and distance radius from it.
+func RegularPolygon(center Point, radius float64, n int, rotation float64) Polygon {
+	if n < 3 {
+		panic("A RegularPolygon needs at least three vertices")
+	}
+	if radius <= 0 {
+		panic("A RegularPolygon needs a positive radius")
+	}
+	pts := make([]Point, n)
+	for i := range pts {
+		sin, cos := math.Sincos(rotation + 2*math.Pi*float64(i)/float64(n))
+		pts[i] = Point{center.x + radius*cos, center.y + radius*sin}
+	}
+	return Polygon{pts}
+}
+func (pg Polygon) shift(dx float64, dy float64) Value {
+	return Polygon{mapPoints(pg.pts, func(p Point) Point { return Point{p.x + dx, p.y + dy} })}
+}
+func (pg Polygon) mirror(fx float64, fy float64) Value {
+	return Polygon{mapPoints(pg.pts, func(p Point) Point { return Point{fx * p.x, fy * p.y} })}
+}
+func (pg Polygon) scale(sx float64, sy float64) Value {
+	return Polygon{mapPoints(pg.pts, func(p Point) Point { return Point{sx * p.x, sy * p.y} })}
+}
+func (pg Polygon) rotate(theta float64) Value {
+	return Polygon{mapPoints(pg.pts, func(p Point) Point { return p.rotate(theta).(Point) })}
+}
+func (pg Polygon) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return pg
+	case Point:
+		if pg.inside(ot) || pg.onBoundary(ot) {
+			return ot
+		} else {
+			return Nowhere
+		}
+	case Line:
+		// start from the point of ot closest to the origin
+		sin, cos := ot.sin, ot.cos
+		return pg.clip(ot, Point{ot.d * sin, ot.d * cos}, Point{cos, -sin})
+	case LineSegment:
+		length := math.Hypot(ot.x2-ot.x1, ot.y2-ot.y1)
+		dir := Point{(ot.x2 - ot.x1) / length, (ot.y2 - ot.y1) / length}
+		return pg.clip(ot, Point{ot.x1, ot.y1}, dir)
+	case Polygon:
+		var parts []Value
+		for _, pw := range PolygonIntersection(pg, ot) {
+			for _, piece := range withoutHoles(pw) {
+				parts = append(parts, piece)
+			}
+		}
+		return newCollection(parts)
+	case pointSet, compoundCurve, Path, Ray, Rect, Triangle, collection:
+		return ot.intersect(pg)
+	case Bezier, Arc:
+		return pg.clipCurve(ot.(Curve))
+	case Circle:
+		// clipped as the arc all the way round, which comes back whole if ot
+		// lies inside pg
+		full := Arc{ot.x, ot.y, ot.r, 0, 2 * math.Pi}
+		r := pg.clipCurve(full)
+		if r == Value(full) {
+			return ot
+		}
+		if cl, ok := r.(collection); ok {
+			// a piece running through angle 0 comes back cut in two
+			first, ok1 := cl.vs[0].(Arc)
+			last, ok2 := cl.vs[len(cl.vs)-1].(Arc)
+			if ok1 && ok2 && first.start == 0 && realClose(last.start+last.sweep, 2*math.Pi) {
+				return pg.clipCurve(Arc{ot.x, ot.y, ot.r, last.start, 2 * math.Pi})
+			}
+		}
+		return r
+	}
+	panic("Should never been reached")
+}
+func (pg Polygon) GoString() string {
+	s := make([]string, len(pg.pts))
+	for i, p := range pg.pts {
+		s[i] = fmt.Sprintf("[%v,%v]", p.x, p.y)
+	}
+	return fmt.Sprintf("{\"Polygon\":[%s]}", strings.Join(s, ","))
+}
+func (pg Polygon) String() string {
+	return "Polygon" + readablePoints(pg.pts)
+}
+
+// clip cuts carrier, a line or segment through o in the unit direction dir,
+// down to the parts inside or on pg.
+func (pg Polygon) clip(carrier Value, o Point, dir Point) Value {
+	at := func(t float64) Point { return Point{o.x + t*dir.x, o.y + t*dir.y} }
+	// hits are the end points of carrier and where it meets the edges
+	var hits []Point
+	if ls, ok := carrier.(LineSegment); ok {
+		hits = []Point{{ls.x1, ls.y1}, {ls.x2, ls.y2}}
+	}
+	for i := range pg.pts {
+		a, b := pg.edge(i)
+		switch r := NewLineSegment(a.x, a.y, b.x, b.y).intersect(carrier).(type) {
+		case Point:
+			hits = append(hits, r)
+		case LineSegment:
+			hits = append(hits, Point{r.x1, r.y1}, Point{r.x2, r.y2})
+		}
+	}
+	param := func(p Point) float64 { return (p.x-o.x)*dir.x + (p.y-o.y)*dir.y }
+	sort.Slice(hits, func(i, j int) bool { return param(hits[i]) < param(hits[j]) })
+	var stops []float64
+	var stopPts []Point
+	for _, p := range hits {
+		if len(stops) == 0 || !realClose(param(p), stops[len(stops)-1]) {
+			stops = append(stops, param(p))
+			stopPts = append(stopPts, p)
+		}
+	}
+	covered := func(t float64) bool {
+		p := at(t)
+		return pg.inside(p) || pg.onBoundary(p)
+	}
+	var parts []Value
+	var start Point
+	for i, t := range stops {
+		ahead := i+1 < len(stops) && covered((t+stops[i+1])/2)
+		behind := i > 0 && covered((stops[i-1]+t)/2)
+		switch {
+		case ahead && !behind:
+			start = stopPts[i]
+		case behind && !ahead:
+			parts = append(parts, NewLineSegment(start.x, start.y, stopPts[i].x, stopPts[i].y))
+		case !ahead && !behind && covered(t):
+			parts = append(parts, stopPts[i])
+		}
+	}
+	return newCollection(parts)
+}
+
+// clipCurve cuts c down to the parts inside or on pg.
+func (pg Polygon) clipCurve(c Curve) Value {
+	return pg.cutCurve(c, func(p Point) bool { return pg.inside(p) || pg.onBoundary(p) })
+}
+
+// cutCurve splits c where it meets the edges of pg and keeps the pieces
+// whose middle satisfies keep, and single points satisfying it between
+// pieces that do not.
+func (pg Polygon) cutCurve(c Curve, keep func(Point) bool) Value {
+	stops := []float64{0, 1}
+	for i := range pg.pts {
+		a, b := pg.edge(i)
+		for _, p := range pointsOf(NewLineSegment(a.x, a.y, b.x, b.y).intersect(c)) {
+			stops = append(stops, curveParam(c, p))
+		}
+	}
+	sort.Float64s(stops)
+	n := 1
+	for _, t := range stops[1:] {
+		if t-stops[n-1] >= epsilon {
+			stops[n] = t
+			n++
+		}
+	}
+	stops[n-1], stops = 1, stops[:n]
+	var parts []Value
+	start := 0.0
+	for i, t := range stops {
+		ahead := i+1 < len(stops) && keep(c.At((t+stops[i+1])/2))
+		behind := i > 0 && keep(c.At((stops[i-1]+t)/2))
+		switch {
+		case ahead && !behind:
+			start = t
+		case behind && !ahead:
+			if start == 0 && t == 1 {
+				return c
+			}
+			parts = append(parts, subCurve(c, start, t))
+		case !ahead && !behind && keep(c.At(t)):
+			parts = append(parts, c.At(t))
+		}
+	}
+	return newCollection(parts)
+}
+
+// boundary is the closed path around pg.
+func (pg Polygon) boundary() Path {
+	return Path{append(append([]Point{}, pg.pts...), pg.pts[0])}
+}
+
+// area is the signed area, positive for counterclockwise polygons.
+func (pg Polygon) area() float64 {
+	a := 0.0
+	for i, p := range pg.pts {
+		q := pg.pts[(i+1)%len(pg.pts)]
+		a += p.x*q.y - q.x*p.y
+	}
+	return a / 2
+}
+
+// counterclockwise returns pg with its vertices in counterclockwise order.
+func (pg Polygon) counterclockwise() Polygon {
+	if pg.area() >= 0 {
+		return pg
+	}
+	pts := make([]Point, len(pg.pts))
+	for i, p := range pg.pts {
+		pts[len(pts)-1-i] = p
+	}
+	return Polygon{pts}
+}
+
+// edge returns the i-th edge, from vertex i to vertex i+1.
+func (pg Polygon) edge(i int) (Point, Point) {
+	return pg.pts[i], pg.pts[(i+1)%len(pg.pts)]
+}
+
+// onBoundary reports whether p lies on an edge of pg.
+func (pg Polygon) onBoundary(p Point) bool {
+	for i := range pg.pts {
+		a, b := pg.edge(i)
+		if _, ok := NewLineSegment(a.x, a.y, b.x, b.y).intersect(p).(Point); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inside reports whether p lies in the interior of pg, using the even-odd
+// crossing rule.
+func (pg Polygon) inside(p Point) bool {
+	in := false
+	for i := range pg.pts {
+		a, b := pg.edge(i)
+		if (a.y > p.y) != (b.y > p.y) && p.x < a.x+(p.y-a.y)*(b.x-a.x)/(b.y-a.y) {
+			in = !in
+		}
+	}
+	return in
+}
+
+// removeCollinear drops vertices lying on the straight line through their
+// neighbours.
+func removeCollinear(pts []Point) []Point {
+	changed := true
+	for changed && len(pts) > 2 {
+		changed = false
+		for i := range pts {
+			p, q, r := pts[(i+len(pts)-1)%len(pts)], pts[i], pts[(i+1)%len(pts)]
+			cross := (q.x-p.x)*(r.y-p.y) - (q.y-p.y)*(r.x-p.x)
+			if math.Abs(cross) < epsilon*math.Hypot(r.x-p.x, r.y-p.y) || realClosePoint(p, q) {
+				pts = append(pts[:i:i], pts[i+1:]...)
+				changed = true
+				break
+			}
+		}
+	}
+	return pts
+}
@@ -0,0 +1,110 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// AreCollinear tells whether all points lie within distance tol of one
+// line.
+func AreCollinear(points []Point, tol float64) bool {
+	if len(points) < 3 {
+		return true
+	}
+	// the line through the first point and the one farthest from it
+	a, b, far := points[0], points[0], 0.0
+	for _, p := range points[1:] {
+		if d := math.Hypot(p.x-a.x, p.y-a.y); d > far {
+			b, far = p, d
+		}
+	}
+	if far <= tol {
+		return true
+	}
+	for _, p := range points {
+		if math.Abs(Orient2D(a, b, p))/far > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// InConvexPosition tells whether every point is a corner of the convex hull
+// of points, i.e. none lies inside the hull, on an edge between two others
+// or on top of another.
+func InConvexPosition(points []Point) bool {
+	return len(hullVertices(points)) == len(points)
+}
+
+// ConvexHull returns the convex hull of points as a counterclockwise
+// Polygon. Duplicates and points on its edges are left out. The hull of
+// points on one line is the LineSegment between the outermost two, of a
+// single point that Point and of no points Nowhere.
+func ConvexHull(points []Point) Value {
+	switch hull := hullVertices(points); len(hull) {
+	case 0:
+		return Nowhere
+	case 1:
+		return hull[0]
+	case 2:
+		return NewLineSegment(hull[0].x, hull[0].y, hull[1].x, hull[1].y)
+	default:
+		return Polygon{hull}
+	}
+}
+
+// hullVertices returns the corners of the convex hull of points in
+// counterclockwise order, leaving out points on its edges and duplicates.
+func hullVertices(points []Point) []Point {
+	var pts []Point
+	for _, p := range points {
+		pts = addPoint(pts, p)
+	}
+	if len(pts) < 3 {
+		return pts
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		return pts[i].x < pts[j].x || (pts[i].x == pts[j].x && pts[i].y < pts[j].y)
+	})
+	// Andrew's monotone chain, lower hull then upper hull
+	var hull []Point
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range pts {
+			for len(hull) >= start+2 && Orient2D(hull[len(hull)-2], hull[len(hull)-1], p) <= epsilon {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		// the last point starts the other chain
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	return hull
+}
@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import "testing"
+
+func TestAreCollinear(t *testing.T) {
+	cases := []struct {
+		name   string
+		points []Point
+		tol    float64
+		want   bool
+	}{
+		{"no points", nil, 0, true},
+		{"one point", []Point{{1, 2}}, 0, true},
+		{"two points", []Point{{0, 0}, {3, 4}}, 0, true},
+		{"three on a line", []Point{{0, 0}, {1, 1}, {3, 3}}, 1e-9, true},
+		{"unordered on a line", []Point{{2, 1}, {-2, -1}, {0, 0}, {4, 2}}, 1e-9, true},
+		{"corner of a triangle", []Point{{0, 0}, {1, 0}, {0, 1}}, 1e-9, false},
+		{"off by less than tol", []Point{{0, 0}, {10, 0}, {5, 0.05}}, 0.1, true},
+		{"off by more than tol", []Point{{0, 0}, {10, 0}, {5, 0.05}}, 0.01, false},
+		{"all the same point", []Point{{1, 1}, {1, 1}, {1, 1}}, 0, true},
+		{"duplicates on a line", []Point{{0, 0}, {0, 0}, {1, 1}, {1, 1}}, 1e-9, true},
+		{"duplicates off a line", []Point{{0, 0}, {0, 0}, {1, 0}, {0, 1}}, 1e-9, false},
+		{"spread within tol", []Point{{0, 0}, {0.01, 0.01}, {0.01, 0}}, 0.1, true},
+	}
+	for _, c := range cases {
+		if got := AreCollinear(c.points, c.tol); got != c.want {
+			t.Errorf("%s: AreCollinear(%v, %g) = %v", c.name, c.points, c.tol, got)
+		}
+	}
+}
+
+func TestInConvexPosition(t *testing.T) {
+	cases := []struct {
+		name   string
+		points []Point
+		want   bool
+	}{
+		{"no points", nil, true},
+		{"one point", []Point{{1, 2}}, true},
+		{"two points", []Point{{0, 0}, {1, 1}}, true},
+		{"two equal points", []Point{{1, 1}, {1, 1}}, false},
+		{"triangle", []Point{{0, 0}, {1, 0}, {0, 1}}, true},
+		{"square in any order", []Point{{0, 0}, {1, 1}, {1, 0}, {0, 1}}, true},
+		{"point inside", []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 1}}, false},
+		{"point on an edge", []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 0}}, false},
+		{"duplicate corner", []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {2, 2}}, false},
+		{"three on a line", []Point{{0, 0}, {1, 0}, {2, 0}}, false},
+		{"four on a line", []Point{{0, 0}, {3, 3}, {1, 1}, {2, 2}}, false},
+		{"regular octagon", RegularPolygon(Point{0, 0}, 1, 8, 0).pts, true},
+	}
+	for _, c := range cases {
+		if got := InConvexPosition(c.points); got != c.want {
+			t.Errorf("%s: InConvexPosition(%v) = %v", c.name, c.points, got)
+		}
+	}
+}
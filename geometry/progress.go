@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Progress counts the finished steps of a long computation and reports
+// them, with the total number of steps, to a hook every interval until it
+// is stopped. Steps may be added from several goroutines at once.
+type Progress struct {
+	done  int64
+	total int64
+	stop  chan struct{}
+}
+
+/* Progress */
+func NewProgress(total int64, interval time.Duration, hook func(done int64, total int64)) *Progress {
+	p := &Progress{total: total, stop: make(chan struct{})}
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				hook(p.Done(), p.total)
+			}
+		}
+	}()
+	return p
+}
+func (p *Progress) Add(steps int64) {
+	atomic.AddInt64(&p.done, steps)
+}
+func (p *Progress) Done() int64 {
+	return atomic.LoadInt64(&p.done)
+}
+
+// Stop ends the reports. The hook is not called after Stop returns unless
+// a call was already under way.
+func (p *Progress) Stop() {
+	close(p.stop)
+}
@@ -0,0 +1,84 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+)
+
+// earth radius in metres used by both projections (the WGS84 semi-major
+// axis, as in EPSG:3857)
+const earthRadius = 6378137.0
+
+// WebMercator projects a latitude and longitude in degrees to Web Mercator
+// (EPSG:3857) coordinates in metres.
+func WebMercator(lat float64, lon float64) Point {
+	phi := lat * math.Pi / 180
+	return Point{earthRadius * lon * math.Pi / 180, earthRadius * math.Log(math.Tan(math.Pi/4+phi/2))}
+}
+
+// InverseWebMercator returns the latitude and longitude in degrees of a
+// Web Mercator point.
+func InverseWebMercator(p Point) (float64, float64) {
+	lat := (2*math.Atan(math.Exp(p.y/earthRadius)) - math.Pi/2) * 180 / math.Pi
+	return lat, p.x / earthRadius * 180 / math.Pi
+}
+
+// TangentPlane projects onto the plane touching the earth, taken as a
+// sphere, at a reference point. x points east and y north, both in metres.
+// Distances are true near the reference point, which makes it the better
+// choice for local work.
+type TangentPlane struct {
+	lat0 float64 // radians
+	lon0 float64 // radians
+}
+
+func NewTangentPlane(lat float64, lon float64) TangentPlane {
+	return TangentPlane{lat * math.Pi / 180, lon * math.Pi / 180}
+}
+
+// Project maps a latitude and longitude in degrees onto the plane. Points
+// on the far side of the earth overlap the near side.
+func (tp TangentPlane) Project(lat float64, lon float64) Point {
+	phi, dLambda := lat*math.Pi/180, lon*math.Pi/180-tp.lon0
+	return Point{
+		earthRadius * math.Cos(phi) * math.Sin(dLambda),
+		earthRadius * (math.Cos(tp.lat0)*math.Sin(phi) - math.Sin(tp.lat0)*math.Cos(phi)*math.Cos(dLambda)),
+	}
+}
+
+// Unproject returns the latitude and longitude in degrees of a point on the
+// near side of the plane.
+func (tp TangentPlane) Unproject(p Point) (float64, float64) {
+	rho := math.Hypot(p.x, p.y)
+	if rho == 0 {
+		return tp.lat0 * 180 / math.Pi, tp.lon0 * 180 / math.Pi
+	}
+	c := math.Asin(math.Min(rho/earthRadius, 1))
+	sinC, cosC := math.Sincos(c)
+	phi := math.Asin(cosC*math.Sin(tp.lat0) + p.y*sinC*math.Cos(tp.lat0)/rho)
+	lambda := tp.lon0 + math.Atan2(p.x*sinC, rho*cosC*math.Cos(tp.lat0)-p.y*sinC*math.Sin(tp.lat0))
+	return phi * 180 / math.Pi, lambda * 180 / math.Pi
+}
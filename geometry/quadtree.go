@@ -0,0 +1,264 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// points a quadtree leaf holds before it is split
+const quadCapacity = 8
+
+// QuadTree holds a changing set of points for nearest neighbour, radius and
+// rectangle queries. Its square root cell grows as points are inserted
+// outside of it.
+type QuadTree struct {
+	root *quadNode
+	size int
+}
+
+// quadNode is a square cell, a leaf with points or split into four
+// children numbered by x half (bit 0) and y half (bit 1); empty children
+// are nil.
+type quadNode struct {
+	minX     float64
+	minY     float64
+	size     float64
+	pts      []Point
+	children *[4]*quadNode
+}
+
+// NewQuadTree returns a tree holding pts.
+func NewQuadTree(pts []Point) *QuadTree {
+	t := &QuadTree{}
+	for _, p := range pts {
+		t.Insert(p)
+	}
+	return t
+}
+
+// Len returns the number of points in the tree.
+func (t *QuadTree) Len() int {
+	return t.size
+}
+
+// Insert adds p to the tree.
+func (t *QuadTree) Insert(p Point) {
+	if math.IsInf(p.x, 0) || math.IsInf(p.y, 0) || math.IsNaN(p.x) || math.IsNaN(p.y) {
+		panic("A QuadTree only holds finite points")
+	}
+	if t.root == nil {
+		t.root = &quadNode{minX: math.Floor(p.x), minY: math.Floor(p.y), size: 1}
+	}
+	// double the root towards p until it covers p
+	for !t.root.contains(p) {
+		old := t.root
+		n := &quadNode{minX: old.minX, minY: old.minY, size: 2 * old.size, children: &[4]*quadNode{}}
+		i := 0
+		if p.x < old.minX {
+			n.minX -= old.size
+			i |= 1
+		}
+		if p.y < old.minY {
+			n.minY -= old.size
+			i |= 2
+		}
+		n.children[i] = old
+		t.root = n
+	}
+	t.root.insert(p)
+	t.size++
+}
+
+// Delete removes one point close to p and reports whether there was one.
+func (t *QuadTree) Delete(p Point) bool {
+	if t.root == nil || !t.root.delete(p) {
+		return false
+	}
+	t.size--
+	return true
+}
+
+// Nearest returns the k points closest to p, nearest first.
+func (t *QuadTree) Nearest(p Point, k int) []Point {
+	if k <= 0 || t.root == nil {
+		return nil
+	}
+	h := &kdHeap{}
+	t.root.nearest(p, k, h)
+	result := make([]Point, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(kdCandidate).p
+	}
+	return result
+}
+
+// Within returns all points at distance at most r from p.
+func (t *QuadTree) Within(p Point, r float64) []Point {
+	var result []Point
+	for _, q := range t.InRect(p.x-r, p.y-r, p.x+r, p.y+r) {
+		if math.Hypot(q.x-p.x, q.y-p.y) <= r+epsilon {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// InRect returns all points inside the rectangle or on its boundary.
+func (t *QuadTree) InRect(minX float64, minY float64, maxX float64, maxY float64) []Point {
+	var result []Point
+	if t.root != nil {
+		t.root.inRect(minX-epsilon, minY-epsilon, maxX+epsilon, maxY+epsilon, &result)
+	}
+	return result
+}
+
+func (n *quadNode) contains(p Point) bool {
+	return n.minX <= p.x && p.x < n.minX+n.size && n.minY <= p.y && p.y < n.minY+n.size
+}
+func (n *quadNode) quadrant(p Point) int {
+	half := n.size / 2
+	i := 0
+	if p.x >= n.minX+half {
+		i |= 1
+	}
+	if p.y >= n.minY+half {
+		i |= 2
+	}
+	return i
+}
+func (n *quadNode) insert(p Point) {
+	for n.children != nil {
+		i := n.quadrant(p)
+		if n.children[i] == nil {
+			half := n.size / 2
+			n.children[i] = &quadNode{minX: n.minX + float64(i&1)*half, minY: n.minY + float64(i>>1)*half, size: half}
+		}
+		n = n.children[i]
+	}
+	n.pts = append(n.pts, p)
+	// cells of points closer than epsilon are not split any further
+	if len(n.pts) > quadCapacity && n.size > epsilon {
+		pts := n.pts
+		n.pts, n.children = nil, &[4]*quadNode{}
+		for _, q := range pts {
+			n.insert(q)
+		}
+	}
+}
+func (n *quadNode) delete(p Point) bool {
+	if n.children == nil {
+		for i, q := range n.pts {
+			if realClosePoint(p, q) {
+				n.pts = append(n.pts[:i], n.pts[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+	for i, c := range n.children {
+		if c != nil && c.distance(p) < 2*epsilon && c.delete(p) {
+			if c.children == nil && len(c.pts) == 0 {
+				n.children[i] = nil
+			}
+			n.merge()
+			return true
+		}
+	}
+	return false
+}
+
+// merge turns n back into a leaf when its children are leaves holding no
+// more points than one leaf may.
+func (n *quadNode) merge() {
+	var pts []Point
+	for _, c := range n.children {
+		if c == nil {
+			continue
+		}
+		if c.children != nil {
+			return
+		}
+		pts = append(pts, c.pts...)
+	}
+	if len(pts) <= quadCapacity {
+		n.pts, n.children = pts, nil
+	}
+}
+
+// distance returns the distance from p to the cell of n, 0 inside it.
+func (n *quadNode) distance(p Point) float64 {
+	dx := math.Max(0, math.Max(n.minX-p.x, p.x-(n.minX+n.size)))
+	dy := math.Max(0, math.Max(n.minY-p.y, p.y-(n.minY+n.size)))
+	return math.Hypot(dx, dy)
+}
+func (n *quadNode) nearest(p Point, k int, h *kdHeap) {
+	if n.children == nil {
+		for _, q := range n.pts {
+			d := math.Hypot(q.x-p.x, q.y-p.y)
+			if h.Len() < k {
+				heap.Push(h, kdCandidate{q, d})
+			} else if d < (*h)[0].dist {
+				(*h)[0] = kdCandidate{q, d}
+				heap.Fix(h, 0)
+			}
+		}
+		return
+	}
+	// visit the nearer children first so the farther ones can be skipped
+	var cs []*quadNode
+	for _, c := range n.children {
+		if c != nil {
+			cs = append(cs, c)
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].distance(p) < cs[j].distance(p) })
+	for _, c := range cs {
+		if h.Len() == k && c.distance(p) >= (*h)[0].dist {
+			return
+		}
+		c.nearest(p, k, h)
+	}
+}
+func (n *quadNode) inRect(minX float64, minY float64, maxX float64, maxY float64, result *[]Point) {
+	if n.minX > maxX || n.minX+n.size < minX || n.minY > maxY || n.minY+n.size < minY {
+		return
+	}
+	if n.children == nil {
+		for _, q := range n.pts {
+			if minX <= q.x && q.x <= maxX && minY <= q.y && q.y <= maxY {
+				*result = append(*result, q)
+			}
+		}
+		return
+	}
+	for _, c := range n.children {
+		if c != nil {
+			c.inRect(minX, minY, maxX, maxY, result)
+		}
+	}
+}
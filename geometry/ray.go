@@ -0,0 +1,140 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+/* ray: starts at (x, y) and runs in direction angle */
+type Ray struct {
+	x     float64
+	y     float64
+	angle float64
+}
+
+func NewRay(x float64, y float64, angle float64) Ray {
+	return Ray{x, y, normalizeAngle(angle)}
+}
+func (r Ray) shift(dx float64, dy float64) Value {
+	return Ray{r.x + dx, r.y + dy, r.angle}
+}
+func (r Ray) mirror(fx float64, fy float64) Value {
+	sin, cos := math.Sincos(r.angle)
+	return NewRay(fx*r.x, fy*r.y, math.Atan2(fy*sin, fx*cos))
+}
+func (r Ray) scale(sx float64, sy float64) Value {
+	sin, cos := math.Sincos(r.angle)
+	return NewRay(sx*r.x, sy*r.y, math.Atan2(sy*sin, sx*cos))
+}
+func (r Ray) rotate(theta float64) Value {
+	p := Point{r.x, r.y}.rotate(theta).(Point)
+	return NewRay(p.x, p.y, r.angle+theta)
+}
+func (r Ray) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return r
+	case Line:
+		if _, ok := ot.intersect(r.toLine()).(Line); ok {
+			return r
+		}
+		return r.clip(ot.intersect(r.toLine()))
+	case Ray:
+		if _, ok := r.toLine().intersect(ot.toLine()).(Line); !ok {
+			return ot.clip(r.clip(r.toLine().intersect(ot.toLine())))
+		}
+		// r and ot are on the same line
+		if realCloseAngle(r.angle, ot.angle) {
+			if r.param(Point{ot.x, ot.y}) > 0 {
+				return ot
+			}
+			return r
+		}
+		return r.clip(ot.clip(NewLineSegment(r.x, r.y, ot.x, ot.y)))
+	case Point, LineSegment, Bezier, Circle, Arc, Polygon, Rect, Triangle:
+		return r.clip(ot.intersect(r.toLine()))
+	case pointSet, compoundCurve, Path, collection:
+		return ot.intersect(r)
+	}
+	panic("Should never been reached")
+}
+func (r Ray) GoString() string {
+	return fmt.Sprintf("{\"Ray\":[%v,%v,%v]}", r.x, r.y, r.angle)
+}
+func (r Ray) String() string {
+	return fmt.Sprintf("Ray(%s, %s, angle=%s)", readable(r.x), readable(r.y), readable(r.angle))
+}
+func (r Ray) Angle() float64 {
+	return r.angle
+}
+func (r Ray) toLine() Line {
+	sin, cos := math.Sincos(r.angle)
+	return lineFromNormal(-sin, cos, cos*r.y-sin*r.x)
+}
+
+// param is the signed distance of p from the origin of r along its direction.
+func (r Ray) param(p Point) float64 {
+	sin, cos := math.Sincos(r.angle)
+	return (p.x-r.x)*cos + (p.y-r.y)*sin
+}
+
+// clip cuts a value lying on the line through r down to the part on r.
+func (r Ray) clip(v Value) Value {
+	switch vt := v.(type) {
+	case Point:
+		if r.param(vt) < -epsilon {
+			return Nowhere
+		}
+		return vt
+	case pointSet:
+		var pts []Point
+		for _, p := range vt.pts {
+			if r.param(p) >= -epsilon {
+				pts = append(pts, p)
+			}
+		}
+		return newPointSet(pts)
+	case LineSegment:
+		p1, p2 := Point{vt.x1, vt.y1}, Point{vt.x2, vt.y2}
+		t1, t2 := r.param(p1), r.param(p2)
+		if t1 > t2 {
+			p1, p2, t1, t2 = p2, p1, t2, t1
+		}
+		if t2 < -epsilon {
+			return Nowhere
+		}
+		if t1 < 0 {
+			p1 = Point{r.x, r.y}
+		}
+		return NewLineSegment(p1.x, p1.y, p2.x, p2.y)
+	case collection:
+		return vt.each(r.clip)
+	}
+	return v
+}
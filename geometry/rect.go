@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+/* rect: the axis-aligned region between two corners */
+type Rect struct {
+	minX float64
+	minY float64
+	maxX float64
+	maxY float64
+}
+
+func NewRect(x1 float64, y1 float64, x2 float64, y2 float64) Value {
+	minX, maxX := math.Min(x1, x2), math.Max(x1, x2)
+	minY, maxY := math.Min(y1, y2), math.Max(y1, y2)
+	if realClose(minX, maxX) || realClose(minY, maxY) {
+		return NewLineSegment(minX, minY, maxX, maxY)
+	}
+	return Rect{minX, minY, maxX, maxY}
+}
+func (r Rect) shift(dx float64, dy float64) Value {
+	return Rect{r.minX + dx, r.minY + dy, r.maxX + dx, r.maxY + dy}
+}
+func (r Rect) mirror(fx float64, fy float64) Value {
+	return NewRect(fx*r.minX, fy*r.minY, fx*r.maxX, fy*r.maxY)
+}
+func (r Rect) scale(sx float64, sy float64) Value {
+	return NewRect(sx*r.minX, sy*r.minY, sx*r.maxX, sy*r.maxY)
+}
+func (r Rect) rotate(theta float64) Value {
+	// quarter turns keep r axis-aligned
+	if q := theta / (math.Pi / 2); realClose(q, math.Round(q)) {
+		p1 := Point{r.minX, r.minY}.rotate(theta).(Point)
+		p2 := Point{r.maxX, r.maxY}.rotate(theta).(Point)
+		return NewRect(p1.x, p1.y, p2.x, p2.y)
+	}
+	return r.toPolygon().rotate(theta)
+}
+func (r Rect) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return r
+	case Point:
+		if r.Contains(ot) {
+			return ot
+		} else {
+			return Nowhere
+		}
+	case Rect:
+		minX, minY := math.Max(r.minX, ot.minX), math.Max(r.minY, ot.minY)
+		maxX, maxY := math.Min(r.maxX, ot.maxX), math.Min(r.maxY, ot.maxY)
+		if minX > maxX+epsilon || minY > maxY+epsilon {
+			return Nowhere
+		}
+		return NewRect(minX, minY, maxX, maxY)
+	case Line, LineSegment, Bezier, Circle, Arc, Polygon, Triangle:
+		return r.toPolygon().intersect(ot)
+	case pointSet, compoundCurve, Path, Ray, collection:
+		return ot.intersect(r)
+	}
+	panic("Should never been reached")
+}
+func (r Rect) GoString() string {
+	return fmt.Sprintf("{\"Rect\":[%v,%v,%v,%v]}", r.minX, r.minY, r.maxX, r.maxY)
+}
+func (r Rect) String() string {
+	return fmt.Sprintf("Rect(%s, %s -> %s, %s)", readable(r.minX), readable(r.minY), readable(r.maxX), readable(r.maxY))
+}
+
+// Contains reports whether p lies inside r or on its boundary.
+func (r Rect) Contains(p Point) bool {
+	return between(r.minX, p.x, r.maxX) && between(r.minY, p.y, r.maxY)
+}
+func (r Rect) toPolygon() Polygon {
+	return Polygon{[]Point{{r.minX, r.minY}, {r.maxX, r.minY}, {r.maxX, r.maxY}, {r.minX, r.maxY}}}
+}
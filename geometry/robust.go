@@ -0,0 +1,128 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"math/big"
+)
+
+// error bounds of the float filters, after Shewchuk's "Adaptive Precision
+// Floating-Point Arithmetic and Fast Robust Geometric Predicates"
+const (
+	machineEpsilon = 1.0 / (1 << 53)
+	ccwErrBound    = (3 + 16*machineEpsilon) * machineEpsilon
+	iccErrBound    = (10 + 96*machineEpsilon) * machineEpsilon
+)
+
+// Orient2D is positive if a, b, c turn counterclockwise, negative if they
+// turn clockwise and 0 if they are exactly collinear. It is twice the signed
+// area of the triangle; the sign is always right, falling back to exact
+// arithmetic when the float result is too close to 0 to trust.
+func Orient2D(a Point, b Point, c Point) float64 {
+	detLeft := (a.x - c.x) * (b.y - c.y)
+	detRight := (a.y - c.y) * (b.x - c.x)
+	det := detLeft - detRight
+	if (detLeft > 0) != (detRight > 0) || detLeft == 0 || detRight == 0 {
+		// no cancellation
+		return det
+	}
+	if !(math.Abs(det) < ccwErrBound*(math.Abs(detLeft)+math.Abs(detRight))) {
+		return det
+	}
+	ax, ay := ratPoint(a)
+	bx, by := ratPoint(b)
+	cx, cy := ratPoint(c)
+	l := mulRat(subRat(ax, cx), subRat(by, cy))
+	r := mulRat(subRat(ay, cy), subRat(bx, cx))
+	f, _ := subRat(l, r).Float64()
+	return f
+}
+
+// InCircle is positive if d lies inside the circle through a, b and c,
+// given counterclockwise, negative if it lies outside and 0 if it lies
+// exactly on it. As for Orient2D the sign is always right.
+func InCircle(a Point, b Point, c Point, d Point) float64 {
+	adx, ady := a.x-d.x, a.y-d.y
+	bdx, bdy := b.x-d.x, b.y-d.y
+	cdx, cdy := c.x-d.x, c.y-d.y
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	aLift, bLift, cLift := adx*adx+ady*ady, bdx*bdx+bdy*bdy, cdx*cdx+cdy*cdy
+	det := aLift*(bdxcdy-cdxbdy) + bLift*(cdxady-adxcdy) + cLift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*aLift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*bLift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*cLift
+	if !(math.Abs(det) <= iccErrBound*permanent) {
+		// also for infinite or NaN coordinates
+		return det
+	}
+	ax, ay := ratPoint(a)
+	bx, by := ratPoint(b)
+	cx, cy := ratPoint(c)
+	dx, dy := ratPoint(d)
+	eadx, eady := subRat(ax, dx), subRat(ay, dy)
+	ebdx, ebdy := subRat(bx, dx), subRat(by, dy)
+	ecdx, ecdy := subRat(cx, dx), subRat(cy, dy)
+	lift := func(x *big.Rat, y *big.Rat) *big.Rat {
+		return addRat(mulRat(x, x), mulRat(y, y))
+	}
+	cross := func(x1 *big.Rat, y1 *big.Rat, x2 *big.Rat, y2 *big.Rat) *big.Rat {
+		return subRat(mulRat(x1, y2), mulRat(x2, y1))
+	}
+	exact := addRat(addRat(
+		mulRat(lift(eadx, eady), cross(ebdx, ebdy, ecdx, ecdy)),
+		mulRat(lift(ebdx, ebdy), cross(ecdx, ecdy, eadx, eady))),
+		mulRat(lift(ecdx, ecdy), cross(eadx, eady, ebdx, ebdy)))
+	f, _ := exact.Float64()
+	return f
+}
+
+func ratPoint(p Point) (*big.Rat, *big.Rat) {
+	return new(big.Rat).SetFloat64(p.x), new(big.Rat).SetFloat64(p.y)
+}
+func addRat(a *big.Rat, b *big.Rat) *big.Rat {
+	return new(big.Rat).Add(a, b)
+}
+func subRat(a *big.Rat, b *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(a, b)
+}
+func mulRat(a *big.Rat, b *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(a, b)
+}
+
+// segmentsCross reports whether s1 and s2 cross at a point inside both,
+// decided exactly so that nearly collinear segments give the same answer in
+// either order.
+func segmentsCross(s1 LineSegment, s2 LineSegment) bool {
+	p1, p2 := Point{s1.x1, s1.y1}, Point{s1.x2, s1.y2}
+	q1, q2 := Point{s2.x1, s2.y1}, Point{s2.x2, s2.y2}
+	return oppositeSigns(Orient2D(p1, p2, q1), Orient2D(p1, p2, q2)) &&
+		oppositeSigns(Orient2D(q1, q2, p1), Orient2D(q1, q2, p2))
+}
+func oppositeSigns(a float64, b float64) bool {
+	return (a > 0 && b < 0) || (a < 0 && b > 0)
+}
@@ -0,0 +1,119 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// linePoints returns two points of ln a unit apart.
+func linePoints(ln Line) (Point, Point) {
+	foot := Point{ln.d * ln.sin, ln.d * ln.cos}
+	return foot, Point{foot.x + ln.cos, foot.y - ln.sin}
+}
+
+// checkLine fails unless want holds the points of orig mapped by f and is
+// normalized: a unit normal, d >= 0 and an angle in [0, 2pi) without -0.
+func checkLine(t *testing.T, name string, orig Line, want Value, f func(Point) Point) {
+	t.Helper()
+	ln, ok := want.(Line)
+	if !ok {
+		t.Fatalf("%s: got %#v, want a Line", name, want)
+	}
+	p, q := linePoints(orig)
+	for _, r := range []Point{f(p), f(q)} {
+		if e := ln.sin*r.x + ln.cos*r.y - ln.d; math.Abs(e) > 1e-9 {
+			t.Errorf("%s: %v is %g off %v", name, r, e, ln)
+		}
+	}
+	if math.Abs(math.Hypot(ln.sin, ln.cos)-1) > 1e-12 {
+		t.Errorf("%s: normal of %v is not a unit vector", name, ln)
+	}
+	if ln.d < 0 || math.Signbit(ln.d) {
+		t.Errorf("%s: d of %v is negative", name, ln)
+	}
+	if a := ln.Angle(); a < 0 || a >= 2*math.Pi || math.Signbit(a) {
+		t.Errorf("%s: angle %g of %v is not in [0, 2pi)", name, a, ln)
+	}
+}
+
+var lineRotations = []struct {
+	name  string
+	angle float64
+	d     float64
+	theta float64
+}{
+	{"horizontal by quarter turn", 0, 1, math.Pi / 2},
+	{"first quadrant by quarter turn", math.Pi / 4, 2, math.Pi / 2},
+	{"second quadrant by half turn", 3 * math.Pi / 4, 2, math.Pi},
+	{"third quadrant by three quarters", 5 * math.Pi / 4, 1, 3 * math.Pi / 2},
+	{"fourth quadrant back by a quarter", 7 * math.Pi / 4, 1, -math.Pi / 2},
+	{"vertical by full turn", math.Pi / 2, 3, 2 * math.Pi},
+	{"angle wrapping past 2pi", 0.1, 1, -0.3},
+	{"several turns", 1, 1, 7 * math.Pi},
+	{"through the origin", math.Pi / 3, 0, 2},
+	{"negative d", 1, -2, 0.5},
+	{"no rotation", 2, 1, 0},
+}
+
+func TestRotateLine(t *testing.T) {
+	for _, c := range lineRotations {
+		ln := NewLine(c.angle, c.d)
+		checkLine(t, c.name, ln, Rotate(c.theta, ln), func(p Point) Point {
+			return p.rotate(c.theta).(Point)
+		})
+	}
+}
+
+func TestRotateLineAround(t *testing.T) {
+	pivots := []Point{{0, 0}, {1, 1}, {-3, 2}, {-1, -4}, {5, -0.5}}
+	for _, c := range lineRotations {
+		for _, pivot := range pivots {
+			ln := NewLine(c.angle, c.d)
+			checkLine(t, c.name, ln, RotateAround(pivot, c.theta, ln), func(p Point) Point {
+				r := Point{p.x - pivot.x, p.y - pivot.y}.rotate(c.theta).(Point)
+				return Point{r.x + pivot.x, r.y + pivot.y}
+			})
+		}
+	}
+}
+
+func TestRotateLineOntoPivot(t *testing.T) {
+	// a line through the pivot keeps going through it, with d crossing 0
+	// on the way back to the origin
+	pivot := Point{2, 0}
+	ln := NewLine(0, 0).shift(2, 0).(Line)
+	for _, theta := range []float64{0, math.Pi / 2, math.Pi, 3 * math.Pi / 2, -math.Pi / 2} {
+		r := RotateAround(pivot, theta, ln).(Line)
+		if e := r.sin*pivot.x + r.cos*pivot.y - r.d; math.Abs(e) > 1e-9 {
+			t.Errorf("rotation by %g moved the line off the pivot by %g", theta, e)
+		}
+		checkLine(t, "onto pivot", ln, r, func(p Point) Point {
+			q := Point{p.x - pivot.x, p.y - pivot.y}.rotate(theta).(Point)
+			return Point{q.x + pivot.x, q.y + pivot.y}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Sample returns n points distributed uniformly along gv: by length along
+// segments, curves and the boundaries of polygons, rectangles and
+// triangles, uniformly over the members of point sets. The same seed gives
+// the same points.
+func Sample(gv Value, n int, seed int64) ([]Point, error) {
+	if n < 0 {
+		return nil, errors.New("cannot sample a negative number of points")
+	}
+	r := rand.New(rand.NewSource(seed))
+	result := make([]Point, n)
+	switch v := gv.(type) {
+	case Point:
+		for i := range result {
+			result[i] = v
+		}
+	case pointSet:
+		for i := range result {
+			result[i] = v.pts[r.Intn(len(v.pts))]
+		}
+	case Curve:
+		pts := curvePoints(v)
+		// cumulative length up to every vertex
+		cum := make([]float64, len(pts))
+		for i := 1; i < len(pts); i++ {
+			cum[i] = cum[i-1] + math.Hypot(pts[i].x-pts[i-1].x, pts[i].y-pts[i-1].y)
+		}
+		total := cum[len(cum)-1]
+		for i := range result {
+			s := r.Float64() * total
+			k := sort.SearchFloat64s(cum, s)
+			if k == 0 {
+				k = 1
+			}
+			result[i] = lerp(pts[k-1], pts[k], (s-cum[k-1])/(cum[k]-cum[k-1]))
+		}
+	case Circle:
+		for i := range result {
+			phi := r.Float64() * 2 * math.Pi
+			result[i] = Point{v.x + v.r*math.Cos(phi), v.y + v.r*math.Sin(phi)}
+		}
+	case Polygon:
+		return Sample(v.boundary(), n, seed)
+	case Triangle:
+		return Sample(v.toPolygon().boundary(), n, seed)
+	case Rect:
+		return Sample(v.toPolygon().boundary(), n, seed)
+	case collection:
+		// every point comes from a member picked at random
+		for i := range result {
+			pts, err := Sample(v.vs[r.Intn(len(v.vs))], 1, r.Int63())
+			if err != nil {
+				return nil, err
+			}
+			result[i] = pts[0]
+		}
+	case nowhere:
+		return nil, errors.New("cannot sample Nowhere")
+	default:
+		return nil, errors.New("cannot sample an unbounded value")
+	}
+	return result, nil
+}
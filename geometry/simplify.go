@@ -0,0 +1,53 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// Simplify rewrites composite values into their smallest equivalent form:
+// members closer than epsilon are merged, nested composites are flattened
+// and composites with a single member collapse to that member.
+func Simplify(gv Value) Value {
+	switch v := gv.(type) {
+	case pointSet:
+		var pts []Point
+		for _, p := range v.pts {
+			pts = addPoint(pts, p)
+		}
+		return newPointSet(pts)
+	case compoundCurve:
+		var parts []Curve
+		for _, c := range v.parts {
+			switch sc := Simplify(c).(type) {
+			case compoundCurve:
+				parts = append(parts, sc.parts...)
+			case Curve:
+				parts = append(parts, sc)
+			}
+		}
+		return newCompoundCurve(parts)
+	case collection:
+		return v.each(Simplify)
+	}
+	return gv
+}
@@ -0,0 +1,149 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// maximum number of snap rounding passes
+const snapRoundPasses = 16
+
+// SnapRound rounds an arrangement of segments onto a grid with spacing
+// grid. Every end point and crossing point makes its grid cell "hot" and
+// every segment is rerouted through the centres of the hot cells it passes.
+// The rounding is repeated until the output no longer changes, so all
+// crossings of the result lie exactly on grid points.
+func SnapRound(segs []LineSegment, grid float64) []LineSegment {
+	for pass := 0; pass < snapRoundPasses; pass++ {
+		hot := hotPixels(segs, grid)
+		var result []LineSegment
+		seen := map[LineSegment]bool{}
+		for _, ls := range segs {
+			centers := ls.hotPixelsOnSegment(hot, grid)
+			for i := 1; i < len(centers); i++ {
+				p, q := centers[i-1], centers[i]
+				if s, ok := NewLineSegment(p.x, p.y, q.x, q.y).(LineSegment); ok && !seen[s] {
+					seen[s] = true
+					result = append(result, s)
+				}
+			}
+		}
+		if sameSegments(segs, result) {
+			return result
+		}
+		segs = result
+	}
+	return segs
+}
+
+// snap returns the centre of the grid cell containing p.
+func snap(p Point, grid float64) Point {
+	return Point{math.Round(p.x/grid) * grid, math.Round(p.y/grid) * grid}
+}
+
+// hotPixels returns the centres of the cells containing an end point or a
+// crossing.
+func hotPixels(segs []LineSegment, grid float64) []Point {
+	seen := map[Point]bool{}
+	var result []Point
+	add := func(p Point) {
+		if c := snap(p, grid); !seen[c] {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	for i, ls := range segs {
+		add(Point{ls.x1, ls.y1})
+		add(Point{ls.x2, ls.y2})
+		for _, other := range segs[i+1:] {
+			switch r := ls.intersect(other).(type) {
+			case Point:
+				add(r)
+			case LineSegment:
+				add(Point{r.x1, r.y1})
+				add(Point{r.x2, r.y2})
+			}
+		}
+	}
+	return result
+}
+
+// hotPixelsOnSegment returns the centres of the hot cells ls passes
+// through, ordered from its first to its second end point.
+func (ls LineSegment) hotPixelsOnSegment(hot []Point, grid float64) []Point {
+	type hit struct {
+		t float64
+		c Point
+	}
+	var hits []hit
+	dx, dy := ls.x2-ls.x1, ls.y2-ls.y1
+	for _, c := range hot {
+		// clip the segment against the cell (Liang-Barsky)
+		t0, t1 := 0.0, 1.0
+		inside := true
+		for _, edge := range [][2]float64{
+			{-dx, ls.x1 - (c.x - grid/2)}, {dx, (c.x + grid/2) - ls.x1},
+			{-dy, ls.y1 - (c.y - grid/2)}, {dy, (c.y + grid/2) - ls.y1},
+		} {
+			p, q := edge[0], edge[1]
+			if p == 0 {
+				if q < 0 {
+					inside = false
+				}
+			} else if t := q / p; p < 0 {
+				t0 = math.Max(t0, t)
+			} else {
+				t1 = math.Min(t1, t)
+			}
+		}
+		if inside && t0 <= t1 {
+			hits = append(hits, hit{(t0 + t1) / 2, c})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].t < hits[j].t })
+	result := make([]Point, len(hits))
+	for i, h := range hits {
+		result[i] = h.c
+	}
+	return result
+}
+
+func sameSegments(a []LineSegment, b []LineSegment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	in := map[LineSegment]bool{}
+	for _, ls := range a {
+		in[ls] = true
+	}
+	for _, ls := range b {
+		if !in[ls] {
+			return false
+		}
+	}
+	return true
+}
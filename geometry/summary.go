@@ -0,0 +1,135 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import "math"
+
+// Summary aggregates a list of values.
+type Summary struct {
+	// number of values of every kind, e.g. "Point" or "LineSegment"
+	Counts map[string]int
+	// bounding box of the bounded values, valid if Bounded is set
+	MinX, MinY, MaxX, MaxY float64
+	Bounded                bool
+	// total length of all segments and curves
+	Length float64
+	// points lying within epsilon of an earlier point
+	DuplicatePoints int
+}
+
+// Summarize computes the Summary of vs.
+func Summarize(vs []Value) Summary {
+	s := Summary{Counts: map[string]int{}}
+	var seen []Point
+	var visit func(v Value)
+	visit = func(v Value) {
+		switch gv := v.(type) {
+		case Point:
+			n := len(seen)
+			if seen = addPoint(seen, gv); len(seen) == n {
+				s.DuplicatePoints++
+			}
+		case pointSet:
+			for _, p := range gv.pts {
+				visit(p)
+			}
+		case Curve:
+			s.Length += curveLength(gv)
+		case Circle:
+			s.Length += 2 * math.Pi * gv.r
+		case Polygon:
+			s.Length += curveLength(gv.boundary())
+		case Rect:
+			s.Length += 2 * (gv.maxX - gv.minX + gv.maxY - gv.minY)
+		case Triangle:
+			visit(gv.toPolygon())
+		case collection:
+			for _, m := range gv.vs {
+				visit(m)
+			}
+		}
+	}
+	for _, v := range vs {
+		s.Counts[kind(v)]++
+		visit(v)
+	}
+	var box Rect
+	box, s.Bounded = CombinedBounds(vs)
+	s.MinX, s.MinY, s.MaxX, s.MaxY = box.minX, box.minY, box.maxX, box.maxY
+	return s
+}
+
+// kind is the name a value is printed under, e.g. "Point".
+func kind(v Value) string {
+	switch vt := v.(type) {
+	case nowhere:
+		return "Nowhere"
+	case everywhere:
+		return "Everywhere"
+	case Point:
+		return "Point"
+	case Line:
+		return "Line"
+	case LineSegment:
+		return "LineSegment"
+	case Ray:
+		return "Ray"
+	case Circle:
+		return "Circle"
+	case Arc:
+		return "Arc"
+	case Bezier:
+		if len(vt.pts) == 3 {
+			return "QuadraticBezier"
+		}
+		return "CubicBezier"
+	case compoundCurve:
+		return "CompoundCurve"
+	case Path:
+		return "Path"
+	case Polygon:
+		return "Polygon"
+	case Rect:
+		return "Rect"
+	case Triangle:
+		return "Triangle"
+	case pointSet:
+		return "PointSet"
+	case collection:
+		return "Collection"
+	}
+	panic("Should never been reached")
+}
+
+// curveLength is the length of c, measured along a flattened copy for
+// curved pieces.
+func curveLength(c Curve) float64 {
+	pts := curvePoints(c)
+	length := 0.0
+	for i := 1; i < len(pts); i++ {
+		length += math.Hypot(pts[i].x-pts[i-1].x, pts[i].y-pts[i-1].y)
+	}
+	return length
+}
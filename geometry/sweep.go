@@ -0,0 +1,197 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// SegmentIntersections returns every point where two or more of segs meet,
+// crossings as well as shared or touching end points, ordered by x and then
+// y. Collinear overlaps contribute the end points of the overlap. It sweeps
+// a vertical line across the segments in the manner of Bentley and Ottmann,
+// so only segments adjacent along the sweep line are intersected: for k
+// crossings that takes O((n+k) log n) queue operations instead of testing
+// all n^2 pairs.
+func SegmentIntersections(segs []LineSegment) []Point {
+	sw := sweep{segs: make([]LineSegment, 0, len(segs))}
+	for _, ls := range segs {
+		p, q := Point{ls.x1, ls.y1}, Point{ls.x2, ls.y2}
+		if realClosePoint(p, q) {
+			continue
+		}
+		if sweepLess(q, p) {
+			p, q = q, p
+		}
+		sw.segs = append(sw.segs, LineSegment{p.x, p.y, q.x, q.y})
+		heap.Push(&sw.events, sweepEvent{p, []int{len(sw.segs) - 1}})
+		heap.Push(&sw.events, sweepEvent{q, nil})
+	}
+	var result []Point
+	reported := pointGrid{}
+	for sw.events.Len() > 0 {
+		ev := heap.Pop(&sw.events).(sweepEvent)
+		for sw.events.Len() > 0 && sw.events[0].p == ev.p {
+			ev.starting = append(ev.starting, heap.Pop(&sw.events).(sweepEvent).starting...)
+		}
+		// a crossing computed from different pairs of segments may differ
+		// in the last bits and then comes up more than once
+		if sw.handle(ev) && reported.add(ev.p) {
+			result = append(result, ev.p)
+		}
+	}
+	return result
+}
+
+// pointGrid buckets points by cells of size epsilon to find close ones.
+type pointGrid map[[2]float64][]Point
+
+// add adds p and reports whether no point close to it was there already.
+func (g pointGrid) add(p Point) bool {
+	cx, cy := math.Floor(p.x/epsilon), math.Floor(p.y/epsilon)
+	for dx := -1.0; dx <= 1; dx++ {
+		for dy := -1.0; dy <= 1; dy++ {
+			for _, q := range g[[2]float64{cx + dx, cy + dy}] {
+				if realClosePoint(p, q) {
+					return false
+				}
+			}
+		}
+	}
+	g[[2]float64{cx, cy}] = append(g[[2]float64{cx, cy}], p)
+	return true
+}
+
+// sweepLess orders points along the sweep, by x and then y.
+func sweepLess(p Point, q Point) bool {
+	return p.x < q.x || (p.x == q.x && p.y < q.y)
+}
+
+type sweepEvent struct {
+	p        Point
+	starting []int // segments whose first end point is p
+}
+type sweepQueue []sweepEvent
+
+func (q sweepQueue) Len() int            { return len(q) }
+func (q sweepQueue) Less(i, j int) bool  { return sweepLess(q[i].p, q[j].p) }
+func (q sweepQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *sweepQueue) Push(x interface{}) { *q = append(*q, x.(sweepEvent)) }
+func (q *sweepQueue) Pop() interface{} {
+	old := *q
+	ev := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return ev
+}
+
+// sweep holds the segments, sorted from their first to their second end
+// point, the pending events and, in status, the segments crossing the
+// sweep line from bottom to top.
+type sweep struct {
+	segs   []LineSegment
+	events sweepQueue
+	status []int
+}
+
+// handle moves the sweep line to ev.p and reports whether two or more
+// segments meet there.
+func (sw *sweep) handle(ev sweepEvent) bool {
+	p := ev.p
+	// the segments through p are adjacent in status
+	lo := sort.Search(len(sw.status), func(i int) bool { return sw.yAt(sw.status[i], p) > p.y-epsilon })
+	hi := lo
+	for hi < len(sw.status) && sw.yAt(sw.status[hi], p) < p.y+epsilon {
+		hi++
+	}
+	meeting := len(ev.starting) + hi - lo
+	// segments going on beyond p are put back ordered by slope, which is
+	// their order right after p
+	var through []int
+	for _, i := range sw.status[lo:hi] {
+		if ls := sw.segs[i]; !realClosePoint(p, Point{ls.x2, ls.y2}) {
+			through = append(through, i)
+		}
+	}
+	through = append(through, ev.starting...)
+	sort.SliceStable(through, func(a int, b int) bool { return sw.slope(through[a]) < sw.slope(through[b]) })
+	status := append(append(append([]int{}, sw.status[:lo]...), through...), sw.status[hi:]...)
+	sw.status = status
+	if len(through) == 0 {
+		if lo > 0 && lo < len(status) {
+			sw.check(status[lo-1], status[lo], p)
+		}
+	} else {
+		if lo > 0 {
+			sw.check(status[lo-1], status[lo], p)
+		}
+		if last := lo + len(through); last < len(status) {
+			sw.check(status[last-1], status[last], p)
+		}
+	}
+	return meeting > 1
+}
+
+// check queues the crossing of segments i and j if it lies beyond p.
+func (sw *sweep) check(i int, j int, p Point) {
+	q, ok := sw.segs[i].intersect(sw.segs[j]).(Point)
+	if !ok {
+		return
+	}
+	// rounding must not move the crossing off a vertical segment or next
+	// to an end point, where it would be out of order with their events
+	for _, ls := range []LineSegment{sw.segs[i], sw.segs[j]} {
+		if ls.x1 == ls.x2 {
+			q.x = ls.x1
+		}
+		for _, e := range []Point{{ls.x1, ls.y1}, {ls.x2, ls.y2}} {
+			if realClosePoint(q, e) {
+				q = e
+			}
+		}
+	}
+	if sweepLess(p, q) && !realClosePoint(p, q) {
+		heap.Push(&sw.events, sweepEvent{q, nil})
+	}
+}
+
+// yAt returns where segment i crosses the sweep line through p. Vertical
+// segments cross it everywhere along their length and report p.y, clamped
+// to that length.
+func (sw *sweep) yAt(i int, p Point) float64 {
+	ls := sw.segs[i]
+	if ls.x1 == ls.x2 {
+		return math.Max(ls.y1, math.Min(p.y, ls.y2))
+	}
+	return ls.y1 + (p.x-ls.x1)*(ls.y2-ls.y1)/(ls.x2-ls.x1)
+}
+func (sw *sweep) slope(i int) float64 {
+	ls := sw.segs[i]
+	if ls.x1 == ls.x2 {
+		return math.Inf(1)
+	}
+	return (ls.y2 - ls.y1) / (ls.x2 - ls.x1)
+}
@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Transform is an affine map of the plane, held as a homogeneous 3×3 matrix
+// whose last row is 0 0 1.
+type Transform struct {
+	m [3][3]float64
+}
+
+/* Transform */
+func Identity() Transform {
+	return Transform{[3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}}
+}
+func Translation(dx float64, dy float64) Transform {
+	return Transform{[3][3]float64{{1, 0, dx}, {0, 1, dy}, {0, 0, 1}}}
+}
+func Rotation(theta float64) Transform {
+	sin, cos := math.Sincos(theta)
+	return Transform{[3][3]float64{{cos, -sin, 0}, {sin, cos, 0}, {0, 0, 1}}}
+}
+func Scaling(sx float64, sy float64) Transform {
+	return Transform{[3][3]float64{{sx, 0, 0}, {0, sy, 0}, {0, 0, 1}}}
+}
+
+// Shearing maps (x, y) to (x + kx*y, y + ky*x).
+func Shearing(kx float64, ky float64) Transform {
+	return Transform{[3][3]float64{{1, kx, 0}, {ky, 1, 0}, {0, 0, 1}}}
+}
+
+// Mul returns the transform applying o first and then t.
+func (t Transform) Mul(o Transform) Transform {
+	var m [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				m[i][j] += t.m[i][k] * o.m[k][j]
+			}
+		}
+	}
+	return Transform{m}
+}
+func (t Transform) Inverse() Transform {
+	a, b, c, d := t.m[0][0], t.m[0][1], t.m[1][0], t.m[1][1]
+	det := a*d - b*c
+	if realClose(det, 0) {
+		panic("Transform is not invertible")
+	}
+	tx, ty := t.m[0][2], t.m[1][2]
+	return Transform{[3][3]float64{
+		{d / det, -b / det, (b*ty - d*tx) / det},
+		{-c / det, a / det, (c*tx - a*ty) / det},
+		{0, 0, 1},
+	}}
+}
+
+// Apply maps gv by t. The linear part is split into a rotation, a scaling
+// along the axes and another rotation. Circles and arcs that t does not
+// scale uniformly, e.g. when it shears, become Bezier ellipses as with
+// Scale.
+func (t Transform) Apply(gv Value) Value {
+	a, b, c, d := t.m[0][0], t.m[0][1], t.m[1][0], t.m[1][1]
+	if realClose(a*d-b*c, 0) {
+		panic("Transform is not invertible")
+	}
+	if b == 0 && c == 0 {
+		return gv.scale(a, d).shift(t.m[0][2], t.m[1][2])
+	}
+	// singular value decomposition of [[a b] [c d]]
+	e, f := (a+d)/2, (a-d)/2
+	g, h := (c+b)/2, (c-b)/2
+	q, r := math.Hypot(e, h), math.Hypot(f, g)
+	a1, a2 := math.Atan2(g, f), math.Atan2(h, e)
+	return gv.rotate((a2-a1)/2).scale(q+r, q-r).rotate((a2+a1)/2).shift(t.m[0][2], t.m[1][2])
+}
+func (t Transform) GoString() string {
+	return fmt.Sprintf("{\"Transform\":[%v,%v,%v,%v,%v,%v]}", t.m[0][0], t.m[0][1], t.m[0][2], t.m[1][0], t.m[1][1], t.m[1][2])
+}
@@ -0,0 +1,134 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+/* triangle: the region spanned by three corners */
+type Triangle struct {
+	a Point
+	b Point
+	c Point
+}
+
+// NewTriangle returns the triangle with the given corners, which must not
+// lie on a common line.
+func NewTriangle(x1 float64, y1 float64, x2 float64, y2 float64, x3 float64, y3 float64) Triangle {
+	t := Triangle{Point{x1, y1}, Point{x2, y2}, Point{x3, y3}}
+	if realClose(t.Area(), 0) {
+		panic("A Triangle needs corners not all on one line")
+	}
+	return t
+}
+
+// newTriangle is NewTriangle for corners that may lie on a common line,
+// which give the segment or point they span.
+func newTriangle(a Point, b Point, c Point) Value {
+	t := Triangle{a, b, c}
+	if realClose(t.Area(), 0) {
+		// the longest of the edges covers the others
+		var longest Value = t.a
+		best := -1.0
+		for _, e := range t.Edges() {
+			if ls, ok := e.(LineSegment); ok && math.Hypot(ls.x2-ls.x1, ls.y2-ls.y1) > best {
+				longest, best = ls, math.Hypot(ls.x2-ls.x1, ls.y2-ls.y1)
+			}
+		}
+		return longest
+	}
+	return t
+}
+func (t Triangle) shift(dx float64, dy float64) Value {
+	return Triangle{t.a.shift(dx, dy).(Point), t.b.shift(dx, dy).(Point), t.c.shift(dx, dy).(Point)}
+}
+func (t Triangle) mirror(fx float64, fy float64) Value {
+	return Triangle{t.a.mirror(fx, fy).(Point), t.b.mirror(fx, fy).(Point), t.c.mirror(fx, fy).(Point)}
+}
+func (t Triangle) scale(sx float64, sy float64) Value {
+	return Triangle{t.a.scale(sx, sy).(Point), t.b.scale(sx, sy).(Point), t.c.scale(sx, sy).(Point)}
+}
+func (t Triangle) rotate(theta float64) Value {
+	return Triangle{t.a.rotate(theta).(Point), t.b.rotate(theta).(Point), t.c.rotate(theta).(Point)}
+}
+func (t Triangle) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return t
+	case Point, Line, LineSegment, Bezier, Circle, Arc, Polygon, Rect, Triangle:
+		return t.toPolygon().intersect(ot)
+	case pointSet, compoundCurve, Path, Ray, collection:
+		return ot.intersect(t)
+	}
+	panic("Should never been reached")
+}
+func (t Triangle) GoString() string {
+	return fmt.Sprintf("{\"Triangle\":[%v,%v,%v,%v,%v,%v]}", t.a.x, t.a.y, t.b.x, t.b.y, t.c.x, t.c.y)
+}
+func (t Triangle) String() string {
+	return "Triangle" + readablePoints([]Point{t.a, t.b, t.c})
+}
+
+// Area is the area of t.
+func (t Triangle) Area() float64 {
+	return math.Abs((t.b.x-t.a.x)*(t.c.y-t.a.y)-(t.c.x-t.a.x)*(t.b.y-t.a.y)) / 2
+}
+
+// Edges returns the sides from a to b, b to c and c to a.
+func (t Triangle) Edges() [3]Value {
+	return [3]Value{
+		NewLineSegment(t.a.x, t.a.y, t.b.x, t.b.y),
+		NewLineSegment(t.b.x, t.b.y, t.c.x, t.c.y),
+		NewLineSegment(t.c.x, t.c.y, t.a.x, t.a.y),
+	}
+}
+
+// Centroid is the common point of the medians.
+func (t Triangle) Centroid() Point {
+	return Point{(t.a.x + t.b.x + t.c.x) / 3, (t.a.y + t.b.y + t.c.y) / 3}
+}
+
+// Circumcenter is the center of the circle through all three corners.
+func (t Triangle) Circumcenter() Point {
+	center, _ := circumcircle(t.a, t.b, t.c)
+	return center
+}
+
+// Incenter is the center of the largest circle inside t, weighting each
+// corner by the length of the opposite side.
+func (t Triangle) Incenter() Point {
+	la := math.Hypot(t.c.x-t.b.x, t.c.y-t.b.y)
+	lb := math.Hypot(t.a.x-t.c.x, t.a.y-t.c.y)
+	lc := math.Hypot(t.b.x-t.a.x, t.b.y-t.a.y)
+	sum := la + lb + lc
+	return Point{(la*t.a.x + lb*t.b.x + lc*t.c.x) / sum, (la*t.a.y + lb*t.b.y + lc*t.c.y) / sum}
+}
+func (t Triangle) toPolygon() Polygon {
+	return Polygon{[]Point{t.a, t.b, t.c}}
+}
@@ -0,0 +1,40 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// UncertainIntersection reports whether the intersection of gv1 and gv2
+// would change its kind or number of components if gv1 moved by band in
+// any axis direction. Such answers sit within the warning band of a
+// tolerance decision, e.g. a line passing a circle at nearly its radius.
+func UncertainIntersection(gv1 Value, gv2 Value, band float64) bool {
+	base := gv1.intersect(gv2)
+	for _, d := range [][2]float64{{band, 0}, {-band, 0}, {0, band}, {0, -band}} {
+		moved := gv1.shift(d[0], d[1]).intersect(gv2)
+		if kind(moved) != kind(base) || len(members(moved)) != len(members(base)) {
+			return true
+		}
+	}
+	return false
+}
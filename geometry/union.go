@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+// Union returns a value covering exactly the points of gv1 and gv2. Regions
+// are merged into one polygon where possible, overlapping or touching
+// collinear segments into one segment, and a value covered by the other
+// disappears; anything else is kept side by side in a Collection.
+func Union(gv1 Value, gv2 Value) Value {
+	switch gv1.(type) {
+	case nowhere:
+		return gv2
+	case everywhere:
+		return Everywhere
+	}
+	switch gv2.(type) {
+	case nowhere:
+		return gv1
+	case everywhere:
+		return Everywhere
+	}
+	if covers(gv2, gv1) {
+		return gv2
+	}
+	if covers(gv1, gv2) {
+		return gv1
+	}
+	if a, ok := regionPolygon(gv1); ok {
+		if b, ok := regionPolygon(gv2); ok {
+			pgs := PolygonUnion(a, b)
+			if len(pgs) == 1 && len(pgs[0].Holes) == 0 {
+				return pgs[0].Outer
+			}
+		}
+	}
+	if a, ok := gv1.(LineSegment); ok {
+		if b, ok := gv2.(LineSegment); ok {
+			if _, ok := a.toLine().intersect(b.toLine()).(Line); ok && !isNowhere(a.intersect(b)) {
+				// both lie on one line and meet, so the extremes span both
+				along := func(p Point) float64 { return (p.x-a.x1)*(a.x2-a.x1) + (p.y-a.y1)*(a.y2-a.y1) }
+				lo, hi := Point{a.x1, a.y1}, Point{a.x2, a.y2}
+				for _, p := range []Point{{b.x1, b.y1}, {b.x2, b.y2}} {
+					if along(p) < along(lo) {
+						lo = p
+					}
+					if along(p) > along(hi) {
+						hi = p
+					}
+				}
+				return NewLineSegment(lo.x, lo.y, hi.x, hi.y)
+			}
+		}
+	}
+	return newCollection([]Value{gv1, gv2})
+}
+
+// regionPolygon returns the polygon bounding a polygon, rect or triangle.
+func regionPolygon(gv Value) (Polygon, bool) {
+	switch v := gv.(type) {
+	case Polygon:
+		return v, true
+	case Rect:
+		return v.toPolygon(), true
+	case Triangle:
+		return v.toPolygon(), true
+	}
+	return Polygon{}, false
+}
+func isNowhere(gv Value) bool {
+	_, ok := gv.(nowhere)
+	return ok
+}
@@ -0,0 +1,205 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// values a ValueIndex leaf holds
+const valueLeafSize = 8
+
+// ValueIndex holds values in a tree of bounding boxes for nearest value
+// and crossing queries.
+type ValueIndex struct {
+	values    []Value
+	boxes     [][4]float64
+	root      *valueNode
+	unbounded []int // lines, rays, Nowhere, Everywhere and collections holding them
+}
+
+type valueNode struct {
+	minX  float64
+	minY  float64
+	maxX  float64
+	maxY  float64
+	items []int // values of a leaf
+	left  *valueNode
+	right *valueNode
+}
+
+// NewValueIndex builds an index of vs. Nowhere and Everywhere are kept but
+// never returned by Nearest, having no distance to a point.
+func NewValueIndex(vs []Value) *ValueIndex {
+	idx := &ValueIndex{values: append([]Value(nil), vs...), boxes: make([][4]float64, len(vs))}
+	var bounded []int
+	for i, v := range vs {
+		minX, minY, maxX, maxY, ok := valueBounds(v)
+		if !ok {
+			idx.unbounded = append(idx.unbounded, i)
+			continue
+		}
+		idx.boxes[i] = [4]float64{minX, minY, maxX, maxY}
+		bounded = append(bounded, i)
+	}
+	idx.root = buildValueNode(bounded, idx.boxes)
+	return idx
+}
+
+// buildValueNode splits items at the median of their box centres along the
+// longer side of their common box.
+func buildValueNode(items []int, boxes [][4]float64) *valueNode {
+	if len(items) == 0 {
+		return nil
+	}
+	n := &valueNode{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+	for _, i := range items {
+		n.minX, n.minY = math.Min(n.minX, boxes[i][0]), math.Min(n.minY, boxes[i][1])
+		n.maxX, n.maxY = math.Max(n.maxX, boxes[i][2]), math.Max(n.maxY, boxes[i][3])
+	}
+	if len(items) <= valueLeafSize {
+		n.items = items
+		return n
+	}
+	axis := 0
+	if n.maxY-n.minY > n.maxX-n.minX {
+		axis = 1
+	}
+	sort.Slice(items, func(a int, b int) bool {
+		ba, bb := boxes[items[a]], boxes[items[b]]
+		return ba[axis]+ba[axis+2] < bb[axis]+bb[axis+2]
+	})
+	m := len(items) / 2
+	n.left, n.right = buildValueNode(items[:m], boxes), buildValueNode(items[m:], boxes)
+	return n
+}
+
+// Len returns the number of values in the index.
+func (idx *ValueIndex) Len() int {
+	return len(idx.values)
+}
+
+// Nearest returns the k values closest to p, nearest first. Values holding
+// p come first, at distance 0. As for Distance, curves and circles are
+// measured along flattened copies.
+func (idx *ValueIndex) Nearest(p Point, k int) []Value {
+	q := &valueQueue{}
+	push := func(i int) {
+		if d, ok := Distance(p, idx.values[i]); ok {
+			heap.Push(q, valueEntry{dist: d, value: i})
+		}
+	}
+	for _, i := range idx.unbounded {
+		push(i)
+	}
+	if idx.root != nil {
+		heap.Push(q, valueEntry{dist: idx.root.distance(p), node: idx.root, value: -1})
+	}
+	var result []Value
+	for len(result) < k && q.Len() > 0 {
+		e := heap.Pop(q).(valueEntry)
+		switch {
+		case e.node == nil:
+			result = append(result, idx.values[e.value])
+		case e.node.left == nil:
+			for _, i := range e.node.items {
+				push(i)
+			}
+		default:
+			for _, c := range []*valueNode{e.node.left, e.node.right} {
+				heap.Push(q, valueEntry{dist: c.distance(p), node: c, value: -1})
+			}
+		}
+	}
+	return result
+}
+
+// CrossingCount returns how many of the values in the index intersect v.
+// Only values whose bounding boxes come within epsilon of v's are
+// intersected.
+func (idx *ValueIndex) CrossingCount(v Value) int {
+	minX, minY, maxX, maxY, ok := valueBounds(v)
+	var candidates []int
+	if ok {
+		box := [4]float64{minX - epsilon, minY - epsilon, maxX + epsilon, maxY + epsilon}
+		candidates = idx.root.overlapping(box, idx.boxes, append([]int(nil), idx.unbounded...))
+	} else {
+		for i := range idx.values {
+			candidates = append(candidates, i)
+		}
+	}
+	count := 0
+	for _, i := range candidates {
+		if !isNowhere(v.intersect(idx.values[i])) {
+			count++
+		}
+	}
+	return count
+}
+
+// overlapping appends the values below n whose boxes overlap box.
+func (n *valueNode) overlapping(box [4]float64, boxes [][4]float64, result []int) []int {
+	if n == nil || !boxesOverlap(box, [4]float64{n.minX, n.minY, n.maxX, n.maxY}) {
+		return result
+	}
+	for _, i := range n.items {
+		if boxesOverlap(box, boxes[i]) {
+			result = append(result, i)
+		}
+	}
+	return n.right.overlapping(box, boxes, n.left.overlapping(box, boxes, result))
+}
+func boxesOverlap(a [4]float64, b [4]float64) bool {
+	return a[0] <= b[2] && b[0] <= a[2] && a[1] <= b[3] && b[1] <= a[3]
+}
+
+// distance returns the distance from p to the box of n, 0 inside it.
+func (n *valueNode) distance(p Point) float64 {
+	dx := math.Max(0, math.Max(n.minX-p.x, p.x-n.maxX))
+	dy := math.Max(0, math.Max(n.minY-p.y, p.y-n.maxY))
+	return math.Hypot(dx, dy)
+}
+
+// valueQueue is a min-heap of nodes, by the distance to their box, and of
+// values, by their own distance.
+type valueEntry struct {
+	dist  float64
+	node  *valueNode
+	value int
+}
+type valueQueue []valueEntry
+
+func (q valueQueue) Len() int            { return len(q) }
+func (q valueQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q valueQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *valueQueue) Push(x interface{}) { *q = append(*q, x.(valueEntry)) }
+func (q *valueQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
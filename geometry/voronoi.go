@@ -0,0 +1,89 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// Voronoi returns the Voronoi cells of points clipped to clip, the region
+// of each point closer to it than to any other. The cells line up with the
+// points: equal points share a cell and a point whose cell misses clip gets
+// Nowhere.
+func Voronoi(points []Point, clip Rect) []Value {
+	sites := distinctPoints(points)
+	result := make([]Value, len(points))
+	for i, s := range points {
+		var others []Point
+		for _, q := range sites {
+			if !realClosePoint(q, s) {
+				others = append(others, q)
+			}
+		}
+		sort.Slice(others, func(a, b int) bool {
+			return math.Hypot(others[a].x-s.x, others[a].y-s.y) < math.Hypot(others[b].x-s.x, others[b].y-s.y)
+		})
+		cell := clip.toPolygon().pts
+		for _, q := range others {
+			// a site farther than twice the farthest corner cannot cut the cell
+			far := 0.0
+			for _, p := range cell {
+				far = math.Max(far, math.Hypot(p.x-s.x, p.y-s.y))
+			}
+			if math.Hypot(q.x-s.x, q.y-s.y) > 2*far {
+				break
+			}
+			cell = cutBisector(cell, s, q)
+			if len(cell) < 3 {
+				break
+			}
+		}
+		result[i] = Nowhere
+		if len(cell) >= 3 && (Polygon{cell}).area() > epsilon*epsilon {
+			result[i] = Polygon{cell}
+		}
+	}
+	return result
+}
+
+// cutBisector keeps the part of the convex polygon pts on the side of s of
+// the perpendicular bisector of s and q.
+func cutBisector(pts []Point, s Point, q Point) []Point {
+	mx, my := (s.x+q.x)/2, (s.y+q.y)/2
+	side := func(p Point) float64 { return (p.x-mx)*(q.x-s.x) + (p.y-my)*(q.y-s.y) }
+	var result []Point
+	for i, a := range pts {
+		b := pts[(i+1)%len(pts)]
+		fa, fb := side(a), side(b)
+		if fa <= 0 {
+			result = addPoint(result, a)
+		}
+		if (fa < 0 && fb > 0) || (fa > 0 && fb < 0) {
+			result = addPoint(result, lerp(a, b, fa/(fa-fb)))
+		}
+	}
+	return result
+}
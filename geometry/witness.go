@@ -0,0 +1,66 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package geometry
+
+import "math"
+
+// Witness is a point where two segments meet together with its parametric
+// positions along each of them: Point = a(T) = b(U) with T, U in [0, 1].
+type Witness struct {
+	Point Point
+	T     float64
+	U     float64
+}
+
+// SegmentWitnesses intersects the line segments a and b and returns the
+// meeting point, or both ends of the shared piece if they overlap. A
+// segment is parameterized from its leftmost (for vertical segments: lowest)
+// end point, the one printed first.
+func SegmentWitnesses(a Value, b Value) []Witness {
+	sa, ok1 := a.(LineSegment)
+	sb, ok2 := b.(LineSegment)
+	if !ok1 || !ok2 {
+		panic("SegmentWitnesses expects two LineSegments")
+	}
+	var pts []Point
+	switch r := sa.intersect(sb).(type) {
+	case Point:
+		pts = []Point{r}
+	case LineSegment:
+		pts = []Point{{r.x1, r.y1}, {r.x2, r.y2}}
+	}
+	ws := make([]Witness, len(pts))
+	for i, p := range pts {
+		ws[i] = Witness{p, sa.param(p), sb.param(p)}
+	}
+	return ws
+}
+
+// param is the position of p, assumed on ls, along ls clamped to [0, 1].
+func (ls LineSegment) param(p Point) float64 {
+	dx, dy := ls.x2-ls.x1, ls.y2-ls.y1
+	t := ((p.x-ls.x1)*dx + (p.y-ls.y1)*dy) / (dx*dx + dy*dy)
+	return math.Max(0, math.Min(1, t))
+}
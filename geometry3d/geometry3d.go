@@ -0,0 +1,357 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+// Package geometry3d is the 3D counterpart of package geometry for points,
+// lines, line segments and planes.
+package geometry3d
+
+import (
+	"fmt"
+	"math"
+)
+
+const epsilon = 0.00001
+
+type Value interface {
+	shift(dx float64, dy float64, dz float64) Value
+	intersect(other Value) Value
+	fmt.GoStringer
+}
+
+type nowhere struct {
+}
+type everywhere struct {
+}
+
+// Point3 is the point (x, y, z).
+type Point3 struct {
+	x float64
+	y float64
+	z float64
+}
+
+// Line3 passes through p in the unit direction dir.
+type Line3 struct {
+	p   Point3
+	dir Point3
+}
+
+// Segment3 runs from p to q.
+type Segment3 struct {
+	p Point3
+	q Point3
+}
+
+// Plane holds the points x with n . x = d, n being a unit normal.
+type Plane struct {
+	n Point3
+	d float64
+}
+
+/* nowhere */
+var Nowhere = nowhere{}
+
+func (nw nowhere) shift(dx float64, dy float64, dz float64) Value {
+	return Nowhere
+}
+func (nw nowhere) intersect(other Value) Value {
+	return Nowhere
+}
+func (nw nowhere) GoString() string {
+	return "\"Nowhere\""
+}
+
+/* everywhere */
+var Everywhere = everywhere{}
+
+func (ew everywhere) shift(dx float64, dy float64, dz float64) Value {
+	return Everywhere
+}
+func (ew everywhere) intersect(other Value) Value {
+	return other
+}
+func (ew everywhere) GoString() string {
+	return "\"Everywhere\""
+}
+
+/* point */
+func NewPoint3(x float64, y float64, z float64) Point3 {
+	return Point3{x, y, z}
+}
+func (p Point3) shift(dx float64, dy float64, dz float64) Value {
+	return Point3{p.x + dx, p.y + dy, p.z + dz}
+}
+func (p Point3) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere:
+		return Nowhere
+	case everywhere:
+		return p
+	case Point3:
+		if realClosePoint(p, ot) {
+			return p
+		}
+		return Nowhere
+	case Line3:
+		if realClosePoint(p, ot.closest(p)) {
+			return p
+		}
+		return Nowhere
+	case Segment3:
+		if realClosePoint(p, ot.at(ot.param(p))) {
+			return p
+		}
+		return Nowhere
+	case Plane:
+		if realClose(dot(ot.n, p), ot.d) {
+			return p
+		}
+		return Nowhere
+	}
+	panic("Should never been reached")
+}
+func (p Point3) GoString() string {
+	return fmt.Sprintf("{\"Point3\":[%v,%v,%v]}", p.x, p.y, p.z)
+}
+func (p Point3) X() float64 {
+	return p.x
+}
+func (p Point3) Y() float64 {
+	return p.y
+}
+func (p Point3) Z() float64 {
+	return p.z
+}
+
+/* line */
+
+// NewLine3 returns the line through (x, y, z) in direction (dx, dy, dz).
+func NewLine3(x float64, y float64, z float64, dx float64, dy float64, dz float64) Line3 {
+	dir := Point3{dx, dy, dz}
+	l := norm(dir)
+	if l < epsilon {
+		panic("A Line3 needs a non-zero direction")
+	}
+	return Line3{Point3{x, y, z}, times(1/l, dir)}
+}
+func (ln Line3) shift(dx float64, dy float64, dz float64) Value {
+	return Line3{ln.p.shift(dx, dy, dz).(Point3), ln.dir}
+}
+func (ln Line3) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere, Point3:
+		return ot.intersect(ln)
+	case everywhere:
+		return ln
+	case Line3:
+		n := cross(ln.dir, ot.dir)
+		if norm(n) < epsilon {
+			// parallel
+			if realClosePoint(ot.p, ln.closest(ot.p)) {
+				return ln
+			}
+			return Nowhere
+		}
+		// the point of ln closest to ot, which must also lie on ot
+		w := minus(ot.p, ln.p)
+		t := dot(cross(w, ot.dir), n) / dot(n, n)
+		return ln.at(t).intersect(ot)
+	case Segment3:
+		switch v := ln.intersect(ot.line()).(type) {
+		case Line3:
+			return ot
+		case Point3:
+			return v.intersect(ot)
+		}
+		return Nowhere
+	case Plane:
+		dn := dot(ln.dir, ot.n)
+		if math.Abs(dn) < epsilon {
+			if realClose(dot(ot.n, ln.p), ot.d) {
+				return ln
+			}
+			return Nowhere
+		}
+		return ln.at((ot.d - dot(ot.n, ln.p)) / dn)
+	}
+	panic("Should never been reached")
+}
+func (ln Line3) GoString() string {
+	return fmt.Sprintf("{\"Line3\":[%v,%v,%v,%v,%v,%v]}", ln.p.x, ln.p.y, ln.p.z, ln.dir.x, ln.dir.y, ln.dir.z)
+}
+func (ln Line3) at(t float64) Point3 {
+	return plus(ln.p, times(t, ln.dir))
+}
+func (ln Line3) closest(p Point3) Point3 {
+	return ln.at(dot(minus(p, ln.p), ln.dir))
+}
+
+/* segment */
+
+// NewSegment3 returns the segment between two points, or a Point3 if they
+// are close.
+func NewSegment3(x1 float64, y1 float64, z1 float64, x2 float64, y2 float64, z2 float64) Value {
+	p, q := Point3{x1, y1, z1}, Point3{x2, y2, z2}
+	if realClosePoint(p, q) {
+		return p
+	}
+	return Segment3{p, q}
+}
+func (s Segment3) shift(dx float64, dy float64, dz float64) Value {
+	return Segment3{s.p.shift(dx, dy, dz).(Point3), s.q.shift(dx, dy, dz).(Point3)}
+}
+func (s Segment3) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere, Point3, Line3:
+		return ot.intersect(s)
+	case everywhere:
+		return s
+	case Segment3:
+		switch v := s.line().intersect(ot.line()).(type) {
+		case Point3:
+			return v.intersect(s).intersect(ot)
+		case Line3:
+			// collinear, overlap the parameter ranges on s
+			t1, t2 := s.param(ot.p), s.param(ot.q)
+			if t1 > t2 {
+				t1, t2 = t2, t1
+			}
+			lo, hi := math.Max(0, t1), math.Min(1, t2)
+			if lo > hi {
+				return Nowhere
+			}
+			a, b := s.at(lo), s.at(hi)
+			return NewSegment3(a.x, a.y, a.z, b.x, b.y, b.z)
+		}
+		return Nowhere
+	case Plane:
+		switch v := s.line().intersect(ot).(type) {
+		case Line3:
+			return s
+		case Point3:
+			return v.intersect(s)
+		}
+		return Nowhere
+	}
+	panic("Should never been reached")
+}
+func (s Segment3) GoString() string {
+	return fmt.Sprintf("{\"Segment3\":[%v,%v,%v,%v,%v,%v]}", s.p.x, s.p.y, s.p.z, s.q.x, s.q.y, s.q.z)
+}
+func (s Segment3) Endpoints() (Point3, Point3) {
+	return s.p, s.q
+}
+func (s Segment3) line() Line3 {
+	d := minus(s.q, s.p)
+	return NewLine3(s.p.x, s.p.y, s.p.z, d.x, d.y, d.z)
+}
+func (s Segment3) at(t float64) Point3 {
+	return plus(s.p, times(t, minus(s.q, s.p)))
+}
+
+// param returns the parameter of the point of s closest to p, clamped to
+// [0, 1] unless p lies on the line of s.
+func (s Segment3) param(p Point3) float64 {
+	d := minus(s.q, s.p)
+	t := dot(minus(p, s.p), d) / dot(d, d)
+	if between(0, t, 1) {
+		return t
+	}
+	return math.Max(0, math.Min(1, t))
+}
+
+/* plane */
+
+// NewPlane returns the plane nx*x + ny*y + nz*z = d.
+func NewPlane(nx float64, ny float64, nz float64, d float64) Plane {
+	n := Point3{nx, ny, nz}
+	l := norm(n)
+	if l < epsilon {
+		panic("A Plane needs a non-zero normal")
+	}
+	return Plane{times(1/l, n), d / l}
+}
+func (pl Plane) shift(dx float64, dy float64, dz float64) Value {
+	return Plane{pl.n, pl.d + dot(pl.n, Point3{dx, dy, dz})}
+}
+func (pl Plane) intersect(other Value) Value {
+	switch ot := other.(type) {
+	case nowhere, Point3, Line3, Segment3:
+		return ot.intersect(pl)
+	case everywhere:
+		return pl
+	case Plane:
+		dir := cross(pl.n, ot.n)
+		if norm(dir) < epsilon {
+			// parallel, the same plane if the normals agree up to sign
+			c := dot(pl.n, ot.n)
+			if realClose(pl.d, c*ot.d) {
+				return pl
+			}
+			return Nowhere
+		}
+		c := dot(pl.n, ot.n)
+		p := times(1/(1-c*c), plus(times(pl.d-ot.d*c, pl.n), times(ot.d-pl.d*c, ot.n)))
+		return NewLine3(p.x, p.y, p.z, dir.x, dir.y, dir.z)
+	}
+	panic("Should never been reached")
+}
+func (pl Plane) GoString() string {
+	return fmt.Sprintf("{\"Plane\":[%v,%v,%v,%v]}", pl.n.x, pl.n.y, pl.n.z, pl.d)
+}
+
+func realClose(f1 float64, f2 float64) bool {
+	return math.Abs(f1-f2) < epsilon
+}
+func realClosePoint(p1 Point3, p2 Point3) bool {
+	return realClose(p1.x, p2.x) && realClose(p1.y, p2.y) && realClose(p1.z, p2.z)
+}
+func between(f1 float64, f2 float64, f3 float64) bool {
+	return math.Min(f1, f3)-epsilon < f2 && f2 < math.Max(f1, f3)+epsilon
+}
+func plus(p Point3, q Point3) Point3 {
+	return Point3{p.x + q.x, p.y + q.y, p.z + q.z}
+}
+func minus(p Point3, q Point3) Point3 {
+	return Point3{p.x - q.x, p.y - q.y, p.z - q.z}
+}
+func times(f float64, p Point3) Point3 {
+	return Point3{f * p.x, f * p.y, f * p.z}
+}
+func dot(p Point3, q Point3) float64 {
+	return p.x*q.x + p.y*q.y + p.z*q.z
+}
+func cross(p Point3, q Point3) Point3 {
+	return Point3{p.y*q.z - p.z*q.y, p.z*q.x - p.x*q.z, p.x*q.y - p.y*q.x}
+}
+func norm(p Point3) float64 {
+	return math.Sqrt(dot(p, p))
+}
+
+func Shift(dx float64, dy float64, dz float64, gv Value) Value {
+	return gv.shift(dx, dy, dz)
+}
+func Intersect(gv1 Value, gv2 Value) Value {
+	return gv1.intersect(gv2)
+}
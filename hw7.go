@@ -26,20 +26,81 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry3d"
 	"io/ioutil"
+	"math"
 	"os"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
-func getValue(data interface{}, env map[string]interface{}, c chan<- interface{}) {
+// EvalError is a failure during evaluation. Path locates the program node
+// that failed, e.g. $.Let.a.Point[1].
+type EvalError struct {
+	Path    string
+	Message string
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// evalPanic carries a panic from an evaluation goroutine to the goroutine
+// waiting for its result.
+type evalPanic struct {
+	value interface{}
+}
+
+var recoverPanics = flag.Bool("recover", true, "turn panics during evaluation into errors; disable to get the original stack trace")
+
+// receive waits for a value from c and re-raises a panic that happened
+// while computing it.
+func receive(c <-chan interface{}) interface{} {
+	v := <-c
+	if p, ok := v.(evalPanic); ok {
+		panic(p.value)
+	}
+	return v
+}
+
+func getValue(data interface{}, env map[string]interface{}, c chan<- interface{}, path string) {
+	defer func() {
+		if !*recoverPanics {
+			return
+		}
+		if r := recover(); r != nil {
+			// keep the path of the innermost failing node
+			if _, ok := r.(*EvalError); !ok {
+				r = &EvalError{path, fmt.Sprint(r)}
+				if logger != nil {
+					logger.Error("node failed", "path", path, "error", r.(*EvalError).Message)
+				}
+			}
+			c <- evalPanic{r}
+		}
+	}()
+	countNode(env)
+	if logger != nil {
+		start := time.Now()
+		logger.Debug("node start", "path", path)
+		defer func() {
+			logger.Debug("node finish", "path", path, "duration", time.Since(start))
+		}()
+	}
 	switch dt := data.(type) {
 	case map[string]interface{}:
 		// eval data
-		c <- eval(dt, env)
+		c <- eval(dt, env, path)
 	case string:
 		// lookup variable
 		if out := env[dt]; out != nil {
+			if from, ok := env[nodeKeyPrefix+dt].(string); ok {
+				passUncertain(env, from, path)
+			}
 			c <- out
 		} else {
 			panic(fmt.Sprintf("Unknown Variable %s", dt))
@@ -50,54 +111,614 @@ func getValue(data interface{}, env map[string]interface{}, c chan<- interface{}
 	}
 }
 
-func getMultipleValues(data []interface{}, env map[string]interface{}) []chan interface{} {
+func getMultipleValues(data []interface{}, env map[string]interface{}, path string) []chan interface{} {
 	var lsChan []chan interface{}
 	for i := range data {
-		c := make(chan interface{})
+		c := make(chan interface{}, 1)
 		lsChan = append(lsChan, c)
-		go getValue(data[i], env, c)
+		start(data[i], env, c, fmt.Sprintf("%s[%d]", path, i))
 	}
 	return lsChan
 }
 
-func eval(prog map[string]interface{}, env map[string]interface{}) interface{} {
+func eval(prog map[string]interface{}, env map[string]interface{}, path string) interface{} {
+	_, styled := prog["style"]
+	_, annotated := prog["uncertain"]
+	strategy, scheduled := prog["strategy"]
+	if styled || annotated || scheduled {
+		// presentation and scheduling only, the value does not depend on it
+		stripped := make(map[string]interface{})
+		for key, data := range prog {
+			if key != "style" && key != "uncertain" && key != "strategy" {
+				stripped[key] = data
+			}
+		}
+		prog = stripped
+	}
+	if scheduled {
+		env = withStrategy(env, strategy)
+	}
 	switch len(prog) {
 	case 1:
 		for cmd, data := range prog {
 			switch cmd {
 			case "Point":
 				if len(data.([]interface{})) == 2 {
-					lsChan := getMultipleValues(data.([]interface{}), env)
-					return geometry.NewPoint((<-lsChan[0]).(float64), (<-lsChan[1]).(float64))
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewPoint(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64))
 				} else {
 					panic("Wrong Parameters Count")
 				}
 			case "Line":
 				if len(data.([]interface{})) == 2 {
-					lsChan := getMultipleValues(data.([]interface{}), env)
-					return geometry.NewLine((<-lsChan[0]).(float64), (<-lsChan[1]).(float64))
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewLine(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Ray":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewRay(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64))
 				} else {
 					panic("Wrong Parameters Count")
 				}
 			case "LineSegment":
 				if len(data.([]interface{})) == 4 {
-					lsChan := getMultipleValues(data.([]interface{}), env)
-					return geometry.NewLineSegment((<-lsChan[0]).(float64), (<-lsChan[1]).(float64), (<-lsChan[2]).(float64), (<-lsChan[3]).(float64))
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewLineSegment(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64))
 				} else {
 					panic("Wrong Parameters Count")
 				}
+			case "Rect":
+				if len(data.([]interface{})) == 4 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewRect(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Triangle":
+				if len(data.([]interface{})) == 6 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewTriangle(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64), receive(lsChan[4]).(float64), receive(lsChan[5]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Circle":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewCircle(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Arc":
+				if len(data.([]interface{})) == 5 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewArc(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64), receive(lsChan[4]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "QuadraticBezier":
+				if len(data.([]interface{})) == 6 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewQuadraticBezier(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64), receive(lsChan[4]).(float64), receive(lsChan[5]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "CubicBezier":
+				if len(data.([]interface{})) == 8 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewCubicBezier(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64), receive(lsChan[4]).(float64), receive(lsChan[5]).(float64), receive(lsChan[6]).(float64), receive(lsChan[7]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Path", "Polygon":
+				var xy []interface{}
+				for _, pt := range data.([]interface{}) {
+					if coords, ok := pt.([]interface{}); ok && len(coords) == 2 {
+						xy = append(xy, coords...)
+					} else {
+						panic(cmd + " expects [x,y] pairs")
+					}
+				}
+				lsChan := getMultipleValues(xy, env, path+"."+cmd)
+				coords := make([]float64, len(lsChan))
+				for i := range lsChan {
+					coords[i] = receive(lsChan[i]).(float64)
+				}
+				if cmd == "Polygon" {
+					return geometry.NewPolygon(coords...)
+				}
+				return geometry.NewPath(coords...)
+			case "PointSet":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				coords := make([]float64, 0, 2*len(lsChan))
+				for i := range lsChan {
+					p, ok := receive(lsChan[i]).(geometry.Point)
+					if !ok {
+						panic("PointSet expects Points")
+					}
+					coords = append(coords, p.X(), p.Y())
+				}
+				return geometry.NewPointSet(coords...)
+			case "Collection":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				vs := make([]geometry.Value, len(lsChan))
+				for i := range lsChan {
+					vs[i] = receive(lsChan[i]).(geometry.Value)
+				}
+				return geometry.NewCollection(vs...)
+			case "Intersects":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.Intersects(receive(lsChan[0]).(geometry.Value), receive(lsChan[1]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Contains":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.Contains(receive(lsChan[0]).(geometry.Value), receive(lsChan[1]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Bounds":
+				if len(data.([]interface{})) == 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					r, ok := geometry.Bounds(receive(lsChan[0]).(geometry.Value))
+					if !ok {
+						panic("Bounds expects a bounded value")
+					}
+					return r
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Aggregate":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				vs := make([]geometry.Value, len(lsChan))
+				for i := range lsChan {
+					vs[i] = receive(lsChan[i]).(geometry.Value)
+				}
+				return geometry.NewAggregate(vs)
+			case "RegularPolygon":
+				if len(data.([]interface{})) == 4 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					center, ok := receive(lsChan[0]).(geometry.Point)
+					if !ok {
+						panic("RegularPolygon expects a Point as center")
+					}
+					n := receive(lsChan[2]).(float64)
+					if n != math.Trunc(n) {
+						panic("RegularPolygon expects a whole number of vertices")
+					}
+					return geometry.RegularPolygon(center, receive(lsChan[1]).(float64), int(n), receive(lsChan[3]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "XOf", "YOf":
+				if len(data.([]interface{})) == 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					p, ok := receive(lsChan[0]).(geometry.Point)
+					if !ok {
+						panic(cmd + " expects a Point")
+					}
+					if cmd == "XOf" {
+						return p.X()
+					}
+					return p.Y()
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Interpolate":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					v, err := geometry.Interpolate(receive(lsChan[0]).(geometry.Value), receive(lsChan[1]).(geometry.Value), receive(lsChan[2]).(float64))
+					if err != nil {
+						panic(err.Error())
+					}
+					return v
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "ClosestPoints":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					p, q, ok := geometry.ClosestPoints(receive(lsChan[0]).(geometry.Value), receive(lsChan[1]).(geometry.Value))
+					if !ok {
+						panic("ClosestPoints of Nowhere or Everywhere is undefined")
+					}
+					return []interface{}{p, q}
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Project":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					p, ok := receive(lsChan[0]).(geometry.Point)
+					if !ok {
+						panic("Project expects a Point")
+					}
+					return geometry.Project(p, receive(lsChan[1]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Distance":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					d, ok := geometry.Distance(receive(lsChan[0]).(geometry.Value), receive(lsChan[1]).(geometry.Value))
+					if !ok {
+						panic("Distance to Nowhere or Everywhere is undefined")
+					}
+					return d
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "AngleOf":
+				if len(data.([]interface{})) == 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					ln, ok := receive(lsChan[0]).(interface{ Angle() float64 })
+					if !ok {
+						panic("AngleOf expects a Line or a Ray")
+					}
+					return ln.Angle()
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "EndpointsOf":
+				if len(data.([]interface{})) == 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					c, ok := receive(lsChan[0]).(geometry.Curve)
+					if !ok {
+						panic("EndpointsOf expects a LineSegment or another curve")
+					}
+					return []interface{}{c.At(0), c.At(1)}
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "AtLength":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					c, ok := receive(lsChan[0]).(geometry.Curve)
+					if !ok {
+						panic("AtLength expects a LineSegment or another curve")
+					}
+					p, err := geometry.AtLength(c, receive(lsChan[1]).(float64))
+					if err != nil {
+						panic(err.Error())
+					}
+					return p
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Frame":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.NewFrame(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "InFrame", "ToWorld", "Transform":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					frame := receive(lsChan[0]).(geometry.Frame)
+					gv := receive(lsChan[1]).(geometry.Value)
+					if cmd == "InFrame" {
+						return frame.ToLocal(gv)
+					}
+					return frame.ToWorld(gv)
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Group":
+				// [frame, members...] with members in the frame's coordinates
+				if len(data.([]interface{})) >= 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					frame := receive(lsChan[0]).(geometry.Frame)
+					members := make([]geometry.Value, len(lsChan)-1)
+					for i := range members {
+						members[i] = receive(lsChan[i+1]).(geometry.Value)
+					}
+					return geometry.NewCollection(geometry.NewGroup(frame, members, nil).Flatten()...)
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "GeoPoint":
+				// [lat, lon] in Web Mercator, [lat, lon, lat0, lon0] on the
+				// tangent plane at (lat0, lon0)
+				switch len(data.([]interface{})) {
+				case 2:
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.WebMercator(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64))
+				case 4:
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					tp := geometry.NewTangentPlane(receive(lsChan[2]).(float64), receive(lsChan[3]).(float64))
+					return tp.Project(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64))
+				default:
+					panic("Wrong Parameters Count")
+				}
 			case "Shift":
 				if len(data.([]interface{})) == 3 {
-					lsChan := getMultipleValues(data.([]interface{}), env)
-					return geometry.Shift((<-lsChan[0]).(float64), (<-lsChan[1]).(float64), (<-lsChan[2]).(geometry.Value))
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.Shift(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Scale":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.Scale(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Rotate":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.Rotate(receive(lsChan[0]).(float64), receive(lsChan[1]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "RotateAround":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					pivot, ok := receive(lsChan[0]).(geometry.Point)
+					if !ok {
+						panic("RotateAround expects a Point as pivot")
+					}
+					return geometry.RotateAround(pivot, receive(lsChan[1]).(float64), receive(lsChan[2]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Reflect":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					axis, ok := receive(lsChan[0]).(geometry.Line)
+					if !ok {
+						panic("Reflect expects a Line as axis")
+					}
+					return geometry.Reflect(axis, receive(lsChan[1]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "LineFromSlopeIntercept":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.LineFromSlopeIntercept(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "ParallelThrough":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					ln, ok := receive(lsChan[0]).(geometry.Line)
+					if !ok {
+						panic("ParallelThrough expects a Line")
+					}
+					p, ok := receive(lsChan[1]).(geometry.Point)
+					if !ok {
+						panic("ParallelThrough expects a Point")
+					}
+					return geometry.ParallelThrough(ln, p)
 				} else {
 					panic("Wrong Parameters Count")
 				}
+			case "AngleBisectors":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					l1, ok1 := receive(lsChan[0]).(geometry.Line)
+					l2, ok2 := receive(lsChan[1]).(geometry.Line)
+					if !ok1 || !ok2 {
+						panic("AngleBisectors expects two Lines")
+					}
+					b1, b2 := geometry.AngleBisectors(l1, l2)
+					return []interface{}{b1, b2}
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "MirrorX", "MirrorY", "MirrorOrigin":
+				if len(data.([]interface{})) == 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					gv := receive(lsChan[0]).(geometry.Value)
+					switch cmd {
+					case "MirrorX":
+						return geometry.MirrorX(gv)
+					case "MirrorY":
+						return geometry.MirrorY(gv)
+					default:
+						return geometry.MirrorOrigin(gv)
+					}
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Dual":
+				if len(data.([]interface{})) == 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry.Dual(receive(lsChan[0]).(geometry.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Value":
+				// the annotated form of a result, {"Value":...,"uncertain":true}
+				c := make(chan interface{}, 1)
+				getValue(data, env, c, path+"."+cmd)
+				v := receive(c)
+				passUncertain(env, path+"."+cmd, path)
+				return v
 			case "Intersect":
-				lsChan := getMultipleValues(data.([]interface{}), env)
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
 				var result geometry.Value = geometry.Everywhere
+				marks, _ := env[uncertainKey].(*uncertainSet)
+				if *intersectTree {
+					vs := make([]geometry.Value, len(lsChan))
+					for i := range lsChan {
+						vs[i] = receive(lsChan[i]).(geometry.Value)
+					}
+					var uncertain int32
+					opts := []geometry.MapReduceOption{geometry.WithTreeOrder()}
+					if sequential(env) {
+						opts = append(opts, geometry.WithWorkers(1))
+					}
+					var err error
+					result, err = geometry.MapReduce(vs, nil, func(gv1 geometry.Value, gv2 geometry.Value) geometry.Value {
+						if marks != nil && geometry.UncertainIntersection(gv1, gv2, *uncertainBand) {
+							atomic.StoreInt32(&uncertain, 1)
+						}
+						return geometry.Intersect(gv1, gv2)
+					}, opts...)
+					if err != nil {
+						panic(&EvalError{path + "." + cmd, err.Error()})
+					}
+					if result == nil {
+						result = geometry.Everywhere
+					}
+					if atomic.LoadInt32(&uncertain) == 1 {
+						marks.mark(path)
+					}
+					return result
+				}
+				uncertain := false
+				for i := range data.([]interface{}) {
+					gv := receive(lsChan[i]).(geometry.Value)
+					if result == geometry.Nowhere {
+						// the remaining arguments cannot change the result but
+						// must still be valid
+						continue
+					}
+					if marks != nil && i > 0 && geometry.UncertainIntersection(result, gv, *uncertainBand) {
+						uncertain = true
+					}
+					result = geometry.Intersect(result, gv)
+				}
+				if uncertain {
+					marks.mark(path)
+				}
+				return result
+			case "Union":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				var result geometry.Value = geometry.Nowhere
 				for i := range data.([]interface{}) {
-					result = geometry.Intersect(result, (<-lsChan[i]).(geometry.Value))
+					result = geometry.Union(result, receive(lsChan[i]).(geometry.Value))
+				}
+				return result
+			case "Difference":
+				if len(data.([]interface{})) == 2 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					v, err := geometry.Difference(receive(lsChan[0]).(geometry.Value), receive(lsChan[1]).(geometry.Value))
+					if err != nil {
+						panic(err.Error())
+					}
+					return v
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "CountIntersections":
+				if len(data.([]interface{})) >= 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					gv := receive(lsChan[0]).(geometry.Value)
+					others := make([]geometry.Value, len(lsChan)-1)
+					for i := range others {
+						others[i] = receive(lsChan[i+1]).(geometry.Value)
+					}
+					return float64(geometry.CrossingCount(gv, others))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "IntersectPairs":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				values := make([]geometry.Value, len(lsChan))
+				for i := range lsChan {
+					values[i] = receive(lsChan[i]).(geometry.Value)
+				}
+				var result []interface{}
+				for _, v := range geometry.PairwiseIntersections(values) {
+					result = append(result, v)
+				}
+				return result
+			case "SegmentIntersections":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				segs := make([]geometry.LineSegment, len(lsChan))
+				for i := range lsChan {
+					segs[i] = receive(lsChan[i]).(geometry.LineSegment)
+				}
+				var result []interface{}
+				for _, p := range geometry.SegmentIntersections(segs) {
+					result = append(result, p)
+				}
+				return result
+			case "ConvexHull":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				pts := make([]geometry.Point, len(lsChan))
+				for i := range lsChan {
+					p, ok := receive(lsChan[i]).(geometry.Point)
+					if !ok {
+						panic("ConvexHull expects Points")
+					}
+					pts[i] = p
+				}
+				return geometry.ConvexHull(pts)
+			case "Voronoi":
+				// [clip rect, points...]
+				if len(data.([]interface{})) >= 1 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					clip, ok := receive(lsChan[0]).(geometry.Rect)
+					if !ok {
+						panic("Voronoi expects a Rect to clip to")
+					}
+					pts := make([]geometry.Point, len(lsChan)-1)
+					for i := range pts {
+						p, ok := receive(lsChan[i+1]).(geometry.Point)
+						if !ok {
+							panic("Voronoi expects Points")
+						}
+						pts[i] = p
+					}
+					var result []interface{}
+					for _, cell := range geometry.Voronoi(pts, clip) {
+						result = append(result, cell)
+					}
+					return result
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Point3":
+				if len(data.([]interface{})) == 3 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry3d.NewPoint3(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Line3", "Segment3":
+				if len(data.([]interface{})) == 6 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					var f [6]float64
+					for i := range f {
+						f[i] = receive(lsChan[i]).(float64)
+					}
+					if cmd == "Line3" {
+						return geometry3d.NewLine3(f[0], f[1], f[2], f[3], f[4], f[5])
+					}
+					return geometry3d.NewSegment3(f[0], f[1], f[2], f[3], f[4], f[5])
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Plane":
+				if len(data.([]interface{})) == 4 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry3d.NewPlane(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(float64))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Shift3":
+				if len(data.([]interface{})) == 4 {
+					lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+					return geometry3d.Shift(receive(lsChan[0]).(float64), receive(lsChan[1]).(float64), receive(lsChan[2]).(float64), receive(lsChan[3]).(geometry3d.Value))
+				} else {
+					panic("Wrong Parameters Count")
+				}
+			case "Intersect3":
+				lsChan := getMultipleValues(data.([]interface{}), env, path+"."+cmd)
+				var result geometry3d.Value = geometry3d.Everywhere
+				for i := range lsChan {
+					result = geometry3d.Intersect(result, receive(lsChan[i]).(geometry3d.Value))
 				}
 				return result
 			}
@@ -113,20 +734,23 @@ func eval(prog map[string]interface{}, env map[string]interface{}) interface{} {
 					var lsName []string
 					for name, exp := range vars {
 						lsName = append(lsName, name)
-						c := make(chan interface{})
+						c := make(chan interface{}, 1)
 						lsChan = append(lsChan, c)
-						go getValue(exp, env, c)
+						start(exp, env, c, path+".Let."+name)
 					}
 					new_env := make(map[string]interface{})
 					for name, value := range env {
 						new_env[name] = value
 					}
 					for i := range lsName {
-						new_env[lsName[i]] = <-lsChan[i]
+						new_env[lsName[i]] = receive(lsChan[i])
+						new_env[nodeKeyPrefix+lsName[i]] = path + ".Let." + lsName[i]
 					}
-					c := make(chan interface{})
-					go getValue(prog["in"], new_env, c)
-					return <-c
+					c := make(chan interface{}, 1)
+					start(prog["in"], new_env, c, path+".in")
+					v := receive(c)
+					passUncertain(env, path+".in", path)
+					return v
 				} else {
 					panic("\"Let\" without \"in\"")
 				}
@@ -138,16 +762,169 @@ func eval(prog map[string]interface{}, env map[string]interface{}) interface{} {
 	}
 }
 
-func main() {
-	prog_raw, _ := ioutil.ReadAll(os.Stdin)
+var useStdlib = flag.Bool("stdlib", true, "predefine xAxis, yAxis, origin, unitCircle and unitSquare")
+
+// stdlib holds the named values available to every program unless
+// disabled with -stdlib=false.
+func stdlib() map[string]interface{} {
+	return map[string]interface{}{
+		"xAxis":      geometry.NewLine(0, 0),
+		"yAxis":      geometry.NewLine(math.Pi/2, 0),
+		"origin":     geometry.NewPoint(0, 0),
+		"unitCircle": geometry.NewCircle(0, 0, 1),
+		"unitSquare": geometry.NewPolygon(0, 0, 1, 0, 1, 1, 0, 1),
+	}
+}
+
+var stdioServer = flag.Bool("stdio-server", false, "evaluate length-prefixed programs from stdin in a loop")
+
+var quiet = flag.Bool("quiet", false, "print nothing but the result")
+var jsonErrors = flag.Bool("json-errors", false, "report errors as {\"Error\":...} on stderr")
+
+// evalProgram parses and evaluates one program and returns the printed
+// result. Panics during evaluation are returned as errors.
+func evalProgram(prog_raw []byte) (string, error) {
 	var prog_data interface{}
 	if err := json.Unmarshal(prog_raw, &prog_data); err != nil {
-		panic(err)
+		return "", err
+	}
+	result, marks, err := evalAnnotated(prog_data)
+	if err != nil {
+		return "", err
 	}
+	if *outputFormat == "json-canonical" {
+		return canonicalJSON(formatAnnotated(result, marks, "$"))
+	}
+	return formatAnnotated(result, marks, "$"), nil
+}
+
+// evalData evaluates an already decoded program.
+func evalData(prog_data interface{}) (interface{}, error) {
+	result, _, err := evalAnnotated(prog_data)
+	return result, err
+}
+
+// evalAnnotated is evalData also returning the results found uncertain under
+// -uncertain-band, nil if the check is off.
+func evalAnnotated(prog_data interface{}) (result interface{}, marks *uncertainSet, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(*EvalError); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
 	env := make(map[string]interface{})
 	env["Nowhere"] = geometry.Nowhere
 	env["Everywhere"] = geometry.Everywhere
-	c := make(chan interface{})
-	go getValue(prog_data, env, c)
-	fmt.Printf("%#v\n", <-c)
+	if *useStdlib {
+		for name, value := range stdlib() {
+			env[name] = value
+		}
+	}
+	define(env)
+	if *uncertainBand > 0 {
+		marks = &uncertainSet{paths: map[string]bool{}}
+		env[uncertainKey] = marks
+	}
+	if *showProgress {
+		p := geometry.NewProgress(countNodes(prog_data), *progressInterval, printProgress)
+		defer p.Stop()
+		env[progressKey] = p
+	}
+	c := make(chan interface{}, 1)
+	go getValue(prog_data, env, c, "$")
+	return receive(c), marks, nil
+}
+
+// format prints a result, with lists as JSON arrays.
+func format(v interface{}) string {
+	if ls, ok := v.([]interface{}); ok {
+		s := make([]string, len(ls))
+		for i := range ls {
+			s[i] = format(ls[i])
+		}
+		return "[" + strings.Join(s, ",") + "]"
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// errorJSON formats err as {"Error":"..."}, with the failing node under
+// "Path" for evaluation errors.
+func errorJSON(err error) string {
+	fields := map[string]string{"Error": err.Error()}
+	if e, ok := err.(*EvalError); ok {
+		fields["Error"] = e.Message
+		fields["Path"] = e.Path
+	}
+	msg, _ := json.Marshal(fields)
+	return string(msg)
+}
+
+// fail reports err according to -quiet and -json-errors and exits.
+func fail(err error) {
+	if *jsonErrors {
+		fmt.Fprintln(os.Stderr, errorJSON(err))
+	} else if !*quiet {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	os.Exit(1)
+}
+
+func main() {
+	flag.Parse()
+	if err := checkFormat(); err != nil {
+		fail(err)
+	}
+	if err := checkStrategy(*evalStrategy); err != nil {
+		fail(err)
+	}
+	setupLogging()
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "stats":
+			if flag.NArg() != 2 {
+				fail(fmt.Errorf("usage: hw7 stats result.json"))
+			}
+			if err := runStats(flag.Arg(1)); err != nil {
+				fail(err)
+			}
+		case "cost":
+			if flag.NArg() != 2 {
+				fail(fmt.Errorf("usage: hw7 cost prog.json"))
+			}
+			if err := runCost(flag.Arg(1)); err != nil {
+				fail(err)
+			}
+		case "run":
+			if err := runBatch(flag.Args()[1:]); err != nil {
+				fail(err)
+			}
+		case "scene-diff":
+			if err := runSceneDiff(flag.Args()[1:]); err != nil {
+				fail(err)
+			}
+		case "interpolate":
+			if err := runInterpolate(flag.Args()[1:]); err != nil {
+				fail(err)
+			}
+		default:
+			fail(fmt.Errorf("unknown command %s", flag.Arg(0)))
+		}
+		return
+	}
+	if *stdioServer {
+		if err := serveStdio(os.Stdin, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+	prog_raw, _ := ioutil.ReadAll(os.Stdin)
+	result, err := evalProgram(prog_raw)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Println(result)
 }
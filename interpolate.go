@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"io/ioutil"
+)
+
+// runInterpolate prints the frames morphing the values of one result file
+// into those of another, one line per frame.
+func runInterpolate(args []string) error {
+	fs := flag.NewFlagSet("interpolate", flag.ExitOnError)
+	frames := fs.Int("frames", 10, "number of steps; frames are printed for t = 0, 1/n, ..., 1")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || *frames < 1 {
+		return fmt.Errorf("usage: hw7 interpolate [--frames n] from.json to.json")
+	}
+	var ends [2][]geometry.Value
+	for i := range ends {
+		raw, err := ioutil.ReadFile(fs.Arg(i))
+		if err != nil {
+			return err
+		}
+		var data interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return err
+		}
+		if ends[i], err = resultValues(data); err != nil {
+			return err
+		}
+	}
+	if len(ends[0]) != len(ends[1]) {
+		return fmt.Errorf("%s has %d values, %s has %d", fs.Arg(0), len(ends[0]), fs.Arg(1), len(ends[1]))
+	}
+	for k := 0; k <= *frames; k++ {
+		t := float64(k) / float64(*frames)
+		frame := make([]interface{}, len(ends[0]))
+		for i := range frame {
+			v, err := geometry.Interpolate(ends[0][i], ends[1][i], t)
+			if err != nil {
+				return err
+			}
+			frame[i] = v
+		}
+		if len(frame) == 1 {
+			fmt.Println(format(frame[0]))
+		} else {
+			fmt.Println(format(frame))
+		}
+	}
+	return nil
+}
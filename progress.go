@@ -0,0 +1,97 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"os"
+	"time"
+)
+
+var showProgress = flag.Bool("progress", false, "report evaluation progress on stderr")
+var progressInterval = flag.Duration("progress-interval", time.Second, "time between progress reports")
+
+// progressKey is the environment entry holding the geometry.Progress
+// counting the evaluated nodes of a program. It is not a valid variable
+// name in programs.
+const progressKey = "$progress"
+
+// countNodes returns the number of nodes getValue is called for when data
+// is evaluated.
+func countNodes(data interface{}) int64 {
+	dt, ok := data.(map[string]interface{})
+	if !ok {
+		return 1
+	}
+	n := int64(1)
+	for key, value := range dt {
+		switch {
+		case key == "style" || key == "uncertain" || key == "strategy":
+		case key == "Let":
+			if vars, ok := value.(map[string]interface{}); ok {
+				for _, exp := range vars {
+					n += countNodes(exp)
+				}
+			}
+		default:
+			n += countArgs(value)
+		}
+	}
+	return n
+}
+
+func countArgs(data interface{}) int64 {
+	ls, ok := data.([]interface{})
+	if !ok {
+		return countNodes(data)
+	}
+	n := int64(0)
+	for _, arg := range ls {
+		// coordinate pairs, e.g. in Path, are not nodes themselves
+		if _, ok := arg.([]interface{}); ok {
+			n += countArgs(arg)
+		} else {
+			n += countNodes(arg)
+		}
+	}
+	return n
+}
+
+// countNode adds the node getValue is called for to the progress in env.
+func countNode(env map[string]interface{}) {
+	if p, ok := env[progressKey].(*geometry.Progress); ok {
+		p.Add(1)
+	}
+}
+
+func printProgress(done int64, total int64) {
+	percent := 100 * float64(done) / float64(total)
+	if percent > 100 {
+		percent = 100
+	}
+	fmt.Fprintf(os.Stderr, "evaluated %d of %d nodes (%.0f%%)\n", done, total, percent)
+}
@@ -0,0 +1,193 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+)
+
+// runSceneDiff prints the geometry.DiffScenes of two result files as JSON
+// and optionally draws it as SVG.
+func runSceneDiff(args []string) error {
+	fs := flag.NewFlagSet("scene-diff", flag.ExitOnError)
+	tol := fs.Float64("tol", 1e-6, "tolerance when matching shapes")
+	svgFile := fs.String("svg", "", "also draw the differences into this SVG file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: hw7 scene-diff [--tol t] [--svg diff.svg] old.json new.json")
+	}
+	var scenes [2][]geometry.Value
+	uncertain := map[string]bool{}
+	for i := range scenes {
+		raw, err := ioutil.ReadFile(fs.Arg(i))
+		if err != nil {
+			return err
+		}
+		var data interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return err
+		}
+		if scenes[i], err = resultValues(data); err != nil {
+			return err
+		}
+		markUncertain(data, uncertain)
+	}
+	d := geometry.DiffScenes(scenes[0], scenes[1], *tol)
+	out, err := json.Marshal(sceneDiffJSON(d))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	if *svgFile != "" {
+		f, err := os.Create(*svgFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return writeDiffSVG(f, d, uncertain)
+	}
+	return nil
+}
+
+// sceneDiffJSON turns d into data printing the values in their usual form.
+func sceneDiffJSON(d geometry.SceneDiff) interface{} {
+	raw := func(vs []geometry.Value) []json.RawMessage {
+		result := make([]json.RawMessage, len(vs))
+		for i, v := range vs {
+			result[i] = json.RawMessage(format(v))
+		}
+		return result
+	}
+	type move struct {
+		From json.RawMessage
+		To   json.RawMessage
+		Dx   float64
+		Dy   float64
+	}
+	moved := make([]move, len(d.Moved))
+	for i, m := range d.Moved {
+		moved[i] = move{json.RawMessage(format(m.From)), json.RawMessage(format(m.To)), m.Dx, m.Dy}
+	}
+	return struct {
+		Added     []json.RawMessage
+		Removed   []json.RawMessage
+		Moved     []move
+		Unchanged int
+	}{raw(d.Added), raw(d.Removed), moved, d.Unchanged}
+}
+
+// svgLayer is a group of values drawn in one color.
+type svgLayer struct {
+	color  string
+	values []geometry.Value
+}
+
+// markUncertain adds the printed forms of the results annotated with
+// "uncertain":true in data to marks.
+func markUncertain(data interface{}, marks map[string]bool) {
+	switch dt := data.(type) {
+	case []interface{}:
+		for _, m := range dt {
+			markUncertain(m, marks)
+		}
+	case map[string]interface{}:
+		if dt["uncertain"] == true {
+			if v, err := evalData(dt); err == nil {
+				if gv, ok := v.(geometry.Value); ok {
+					marks[gv.GoString()] = true
+				}
+			}
+		}
+	}
+}
+
+// writeDiffSVG draws removed shapes red, added shapes green and moved
+// shapes grey at their old and blue at their new place. Shapes in
+// uncertain get a wide orange halo.
+func writeDiffSVG(w io.Writer, d geometry.SceneDiff, uncertain map[string]bool) error {
+	var from, to []geometry.Value
+	for _, m := range d.Moved {
+		from, to = append(from, m.From), append(to, m.To)
+	}
+	layers := []svgLayer{{"red", d.Removed}, {"green", d.Added}, {"grey", from}, {"blue", to}}
+	var all []geometry.Value
+	for _, l := range layers {
+		all = append(all, l.values...)
+	}
+	s := geometry.Summarize(all)
+	minX, minY, maxX, maxY := s.MinX-1, s.MinY-1, s.MaxX+1, s.MaxY+1
+	view := geometry.NewRect(minX, minY, maxX, maxY)
+	width := math.Max(maxX-minX, maxY-minY) / 500
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%v %v %v %v\">\n", minX, -maxY, maxX-minX, maxY-minY)
+	var halo []geometry.Value
+	for _, l := range layers {
+		for _, v := range l.values {
+			if uncertain[v.GoString()] {
+				halo = append(halo, v)
+			}
+		}
+	}
+	layers = append([]svgLayer{{"orange", halo}}, layers...)
+	for i, l := range layers {
+		stroke := width
+		if i == 0 {
+			stroke = 5 * width
+		}
+		fmt.Fprintf(&b, "<g stroke=\"%s\" fill=\"none\" stroke-width=\"%v\">\n", l.color, stroke)
+		for _, v := range l.values {
+			pls := geometry.Polylines(v)
+			if pls == nil {
+				// unbounded shapes are cut to the drawing area
+				pls = geometry.Polylines(geometry.Intersect(v, view))
+			}
+			for _, pl := range pls {
+				if len(pl) == 1 {
+					fmt.Fprintf(&b, "<circle cx=\"%v\" cy=\"%v\" r=\"%v\" fill=\"%s\"/>\n", pl[0].X(), -pl[0].Y(), 3*stroke, l.color)
+					continue
+				}
+				coords := make([]string, len(pl))
+				for i, p := range pl {
+					coords[i] = fmt.Sprintf("%v,%v", p.X(), -p.Y())
+				}
+				fmt.Fprintf(&b, "<polyline points=\"%s\"/>\n", strings.Join(coords, " "))
+			}
+		}
+		fmt.Fprintln(&b, "</g>")
+	}
+	fmt.Fprintln(&b, "</svg>")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
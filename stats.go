@@ -0,0 +1,91 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/LesterKort/coursera-programming-languages-part-c-hw7-go/geometry"
+	"io/ioutil"
+)
+
+// runStats prints a geometry.Summary of a result file as JSON.
+func runStats(file string) error {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	values, err := resultValues(data)
+	if err != nil {
+		return err
+	}
+	out, err := json.Marshal(geometry.Summarize(values))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// resultValues reads printed results back by evaluating them as programs.
+// Lists, PointSets, CompoundCurves and Collections are taken apart into
+// their members.
+func resultValues(data interface{}) ([]geometry.Value, error) {
+	var members []interface{}
+	switch dt := data.(type) {
+	case []interface{}:
+		members = dt
+	case map[string]interface{}:
+		for _, key := range []string{"PointSet", "CompoundCurve", "Collection"} {
+			if ls, ok := dt[key].([]interface{}); ok && len(dt) == 1 {
+				members = ls
+			}
+		}
+	}
+	if members == nil {
+		v, err := evalData(data)
+		if err != nil {
+			return nil, err
+		}
+		gv, ok := v.(geometry.Value)
+		if !ok {
+			return nil, fmt.Errorf("not a geometry value: %v", v)
+		}
+		return []geometry.Value{gv}, nil
+	}
+	var result []geometry.Value
+	for _, m := range members {
+		vs, err := resultValues(m)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vs...)
+	}
+	return result, nil
+}
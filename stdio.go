@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxMessageSize bounds the length read from a message header, so that a
+// bad header cannot make the server allocate gigabytes.
+const maxMessageSize = 64 << 20
+
+// serveStdio answers programs sent as messages until r is closed. Every
+// message, in both directions, is a 4 byte big-endian length followed by
+// that many bytes. A program that fails is answered with {"Error":"..."}.
+func serveStdio(r io.Reader, w io.Writer) error {
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	for {
+		prog_raw, err := readMessage(in)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		result, err := evalProgram(prog_raw)
+		if err != nil {
+			result = errorJSON(err)
+		}
+		if err := writeMessage(out, []byte(result)); err != nil {
+			return err
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func readMessage(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds the limit of %d", size, maxMessageSize)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func writeMessage(w io.Writer, msg []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(msg))); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+var evalStrategy = flag.String("strategy", "parallel", "evaluate the arguments of a node in parallel or sequential; a \"strategy\" key on a node overrides it for that subtree")
+
+var intersectTree = flag.Bool("intersect-tree", false, "intersect the arguments of Intersect pairwise in a balanced tree, in parallel, instead of from left to right")
+
+// strategyKey is the environment entry holding the strategy of a subtree set
+// with a "strategy" key. It is not a valid variable name in programs.
+const strategyKey = "$strategy"
+
+// checkStrategy rejects unknown strategies.
+func checkStrategy(s string) error {
+	switch s {
+	case "parallel", "sequential":
+		return nil
+	}
+	return fmt.Errorf("unknown strategy %s", s)
+}
+
+// sequential tells whether nodes evaluated in env run one after another.
+func sequential(env map[string]interface{}) bool {
+	if s, ok := env[strategyKey].(string); ok {
+		return s == "sequential"
+	}
+	return *evalStrategy == "sequential"
+}
+
+// withStrategy returns a copy of env whose nodes are evaluated by strategy s.
+func withStrategy(env map[string]interface{}, s interface{}) map[string]interface{} {
+	name, ok := s.(string)
+	if !ok || checkStrategy(name) != nil {
+		panic(fmt.Sprintf("Unknown Strategy %v", s))
+	}
+	new_env := make(map[string]interface{})
+	for name, value := range env {
+		new_env[name] = value
+	}
+	new_env[strategyKey] = name
+	return new_env
+}
+
+// start evaluates data into c, in a new goroutine unless env is sequential.
+func start(data interface{}, env map[string]interface{}, c chan<- interface{}, path string) {
+	if sequential(env) {
+		getValue(data, env, c, path)
+	} else {
+		go getValue(data, env, c, path)
+	}
+}
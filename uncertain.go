@@ -0,0 +1,86 @@
+/*
+ * MIT License
+ * 
+ * Copyright 2020 Lester Kortenhoeven
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+ * FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER
+ * DEALINGS IN THE SOFTWARE.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var uncertainBand = flag.Float64("uncertain-band", 0, "annotate Intersect results that change when an argument moves by this distance with \"uncertain\":true; 0 turns the check off")
+
+// uncertainKey is the environment entry holding the uncertainSet of an
+// evaluation. It is not a valid variable name in programs.
+const uncertainKey = "$uncertain"
+
+// nodeKeyPrefix starts the environment entry holding the path of the node
+// a Let variable was bound to, so that uncertain marks follow the variable.
+const nodeKeyPrefix = "$node."
+
+// uncertainSet collects the paths of the nodes whose results are uncertain.
+// A node passing on the result of another, as Let, Value and variables do,
+// is marked together with it.
+type uncertainSet struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+func (s *uncertainSet) mark(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths[path] = true
+}
+func (s *uncertainSet) has(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paths[path]
+}
+
+// passUncertain marks the node at path to if the node at path from is
+// marked in env.
+func passUncertain(env map[string]interface{}, from string, to string) {
+	if marks, ok := env[uncertainKey].(*uncertainSet); ok && marks.has(from) {
+		marks.mark(to)
+	}
+}
+
+// formatAnnotated is format with the results of uncertain nodes, the
+// program itself at "$" and the members of a resulting list below it,
+// wrapped as {"Value":...,"uncertain":true}.
+func formatAnnotated(v interface{}, s *uncertainSet, path string) string {
+	if ls, ok := v.([]interface{}); ok {
+		parts := make([]string, len(ls))
+		for i := range ls {
+			parts[i] = formatAnnotated(ls[i], s, fmt.Sprintf("%s[%d]", path, i))
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	}
+	if s != nil && s.has(path) {
+		return "{\"Value\":" + format(v) + ",\"uncertain\":true}"
+	}
+	return format(v)
+}